@@ -0,0 +1,101 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+var answers = map[string]any{}
+
+var unrecordedFlags = []string{"admin-password", "admin-pass", "record-answers", "answers", "policy-sha256", "dry-run", "resume", "v", "vv", "log-file", "output", "quiet"}
+
+func recordAnswer(name, value string) {
+	if containsString(unrecordedFlags, name) {
+		return
+	}
+	answers[name] = value
+}
+
+func recordFlagAnswers(fs *flag.FlagSet) {
+	if opts.recordAnswers == "" {
+		return
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if containsString(unrecordedFlags, f.Name) {
+			return
+		}
+		switch v := f.Value.(type) {
+		case *stringListFlag:
+			answers[f.Name] = []string(*v)
+		case keyValueFlag:
+			pairs := strings.Split(v.String(), ",")
+			sort.Strings(pairs)
+			answers[f.Name] = pairs
+		default:
+			answers[f.Name] = f.Value.String()
+		}
+	})
+}
+
+func applyAnswersFile(fs *flag.FlagSet, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var saved map[string]any
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	return applyAnswers(fs, path, saved)
+}
+
+func applyAnswers(fs *flag.FlagSet, source string, saved map[string]any) error {
+	for _, name := range sortedKeys(saved) {
+		if opts.flagSet[name] {
+			continue
+		}
+		if fs.Lookup(name) == nil || containsString(unrecordedFlags, name) {
+			return fmt.Errorf("%s: unknown answer %q", source, name)
+		}
+		var values []string
+		switch v := saved[name].(type) {
+		case string:
+			values = []string{v}
+		case bool:
+			values = []string{fmt.Sprint(v)}
+		case []any:
+			for _, item := range v {
+				values = append(values, fmt.Sprint(item))
+			}
+		default:
+			return fmt.Errorf("%s: answer %q must be a string or a list", source, name)
+		}
+		for _, value := range values {
+			if err := fs.Set(name, value); err != nil {
+				return fmt.Errorf("%s: answer %q: %v", source, name, err)
+			}
+		}
+		opts.flagSet[name] = true
+	}
+	return nil
+}
+
+func writeAnswers() {
+	if opts.recordAnswers == "" {
+		return
+	}
+	data, err := json.MarshalIndent(answers, "", "    ")
+	if err != nil {
+		printWarning(fmt.Sprintf("Could not record answers: %v", err))
+		return
+	}
+	if err := writeFile(opts.recordAnswers, append(data, '\n')); err != nil {
+		printWarning(fmt.Sprintf("Could not write %s: %v", opts.recordAnswers, err))
+		return
+	}
+	printSuccess(fmt.Sprintf("✓ Answers recorded in %s; replay them with --answers %s", opts.recordAnswers, opts.recordAnswers))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+)
+
+var recaptchaTestKeys = [][2]string{
+	{"site_key", "6LeIxAcTAAAAAJcZVRqyHh4HbJ7m7fdLrIwAAAAJ"},
+	{"secret_key", "6LeIxAcTAAAAAGG-vFI1TnRWxMZNFuojJ4WifJWe"},
+}
+
+func init() {
+	registerPreset(&preset{
+		name:        "antispam",
+		description: "Honeypot, Antibot and reCAPTCHA on the contact, registration, password reset and webform forms, with reCAPTCHA test keys for local development",
+		packages:    []string{"drupal/honeypot", "drupal/antibot", "drupal/captcha", "drupal/recaptcha"},
+		modules:     []string{"honeypot", "antibot", "captcha", "recaptcha"},
+		settings: []configSetting{
+			{name: "honeypot.settings", values: [][2]string{
+				{"element_name", "url"},
+				{"time_limit", "5"},
+				{"log", "true"},
+				{"form_settings.user_register_form", "true"},
+				{"form_settings.user_pass", "true"},
+				{"form_settings.contact_message_feedback_form", "true"},
+				{"form_settings.contact_message_personal_form", "true"},
+			}},
+			{name: "captcha.settings", values: [][2]string{
+				{"default_challenge", "recaptcha/reCAPTCHA"},
+				{"log_wrong_responses", "true"},
+			}},
+		},
+		tour: []tourStop{
+			{path: "/admin/config/people/captcha", label: "CAPTCHA settings and protected forms"},
+			{path: "/admin/config/people/captcha/recaptcha", label: "reCAPTCHA keys (set real ones for production)"},
+			{path: "/admin/config/content/honeypot", label: "Honeypot settings"},
+			{path: "/admin/config/user-interface/antibot", label: "Antibot protected forms"},
+		},
+		apply: applyAntispam,
+	})
+}
+
+func applyAntispam(projectPath string) error {
+	if presetActive("webform") {
+		if err := applyConfigSettings(projectPath, []configSetting{
+			{name: "webform.settings", values: [][2]string{
+				{"third_party_settings.honeypot.honeypot", "true"},
+			}},
+		}); err != nil {
+			return err
+		}
+	}
+
+	path := filepath.Join(siteDefaultDir(projectPath), "settings.local.php")
+	f, err := openSettingsFile(path)
+	if os.IsNotExist(err) {
+		printWarning("No settings.local.php; set the reCAPTCHA keys at /admin/config/people/captcha/recaptcha")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, key := range recaptchaTestKeys {
+		f.set("$config['recaptcha.settings']['"+key[0]+"']", phpString(key[1]))
+	}
+	if err := f.save(); err != nil {
+		printError("Failed to write the reCAPTCHA test keys to settings.local.php")
+		return err
+	}
+	printSuccess("✓ reCAPTCHA test keys set in settings.local.php; production needs its own keys in recaptcha.settings")
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"debug/macho"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	appleSiliconBrewPrefix = "/opt/homebrew"
+	intelBrewPrefix        = "/usr/local"
+)
+
+var archCheckedCommands = []string{"brew", "docker", "colima", "limactl", "ddev"}
+
+func binaryArchs(path string) ([]string, error) {
+	if fat, err := macho.OpenFat(path); err == nil {
+		defer fat.Close()
+		var archs []string
+		for _, arch := range fat.Arches {
+			archs = append(archs, machoArch(arch.Cpu))
+		}
+		return archs, nil
+	}
+	f, err := macho.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return []string{machoArch(f.Cpu)}, nil
+}
+
+func machoArch(cpu macho.Cpu) string {
+	switch cpu {
+	case macho.CpuArm64:
+		return "arm64"
+	case macho.CpuAmd64:
+		return "amd64"
+	}
+	return strings.TrimPrefix(cpu.String(), "Cpu")
+}
+
+func brewPrefix() string {
+	output, err := exec.Command("brew", "--prefix").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func intelBrewInstalled(path string) bool {
+	for _, dir := range []string{"Cellar", "Caskroom"} {
+		if strings.HasPrefix(path, filepath.Join(intelBrewPrefix, dir)+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func architectureProblems(host hostInfo) []string {
+	if runtime.GOOS != "darwin" {
+		return nil
+	}
+	cpu, wantPrefix, otherPrefix := "amd64", intelBrewPrefix, appleSiliconBrewPrefix
+	if host.appleSilicon {
+		cpu, wantPrefix, otherPrefix = "arm64", appleSiliconBrewPrefix, intelBrewPrefix
+	}
+
+	var problems []string
+	if host.rosetta {
+		problems = append(problems, "this terminal runs under Rosetta, so Homebrew installs Intel builds; open a native terminal (uncheck 'Open using Rosetta')")
+	}
+	if prefix := brewPrefix(); prefix == otherPrefix {
+		problems = append(problems, fmt.Sprintf("brew on PATH uses %s, the prefix for the other architecture; install Homebrew into %s and put %s/bin first on PATH", prefix, wantPrefix, wantPrefix))
+	}
+	for _, name := range archCheckedCommands {
+		path, err := exec.LookPath(name)
+		if err != nil {
+			continue
+		}
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			path = resolved
+		}
+		archs, err := binaryArchs(path)
+		if err != nil {
+			continue
+		}
+		if !containsString(archs, cpu) {
+			problems = append(problems, fmt.Sprintf("%s (%s) is built for %s, not this Mac's %s; reinstall it with the %s Homebrew", name, path, strings.Join(archs, "/"), cpu, wantPrefix))
+			continue
+		}
+		if host.appleSilicon && intelBrewInstalled(path) {
+			problems = append(problems, fmt.Sprintf("%s is installed by the Intel Homebrew (%s); reinstall it with the %s Homebrew", name, path, wantPrefix))
+		}
+	}
+	return problems
+}
+
+func warnArchitectureMismatches() {
+	for _, problem := range architectureProblems(detectHost()) {
+		printWarning("✗ " + problem)
+	}
+}
@@ -0,0 +1,545 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func archiveExcludes(projectPath string) []string {
+	docroot := projectDocroot(projectPath)
+	return []string{
+		"vendor",
+		"node_modules",
+		docroot + "/core",
+		docroot + "/modules/contrib",
+		docroot + "/themes/contrib",
+		docroot + "/profiles/contrib",
+		".ddev/.homeadditions",
+		".ddev/db_snapshots",
+	}
+}
+
+type archiveManifest struct {
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	URL        string    `json:"url"`
+	GitRemote  string    `json:"git_remote,omitempty"`
+	GitCommit  string    `json:"git_commit,omitempty"`
+	PHPVersion string    `json:"php_version,omitempty"`
+	Database   string    `json:"database,omitempty"`
+	Excluded   []string  `json:"excluded"`
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+type archivedProject struct {
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	Bucket     string    `json:"bucket"`
+	Key        string    `json:"key"`
+	SHA256     string    `json:"sha256"`
+	Size       int64     `json:"size"`
+	ArchivedAt time.Time `json:"archived_at"`
+}
+
+func archivesPath() (string, error) {
+	dir, err := userConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "archives.json"), nil
+}
+
+func loadArchives() ([]archivedProject, error) {
+	path, err := archivesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var archives []archivedProject
+	if err := json.Unmarshal(data, &archives); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return archives, nil
+}
+
+func saveArchives(archives []archivedProject) error {
+	path, err := archivesPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(archives, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(path, append(data, '\n'))
+}
+
+func findArchive(name string) (archivedProject, bool) {
+	archives, err := loadArchives()
+	if err != nil {
+		return archivedProject{}, false
+	}
+	for i := len(archives) - 1; i >= 0; i-- {
+		if archives[i].Name == name {
+			return archives[i], true
+		}
+	}
+	return archivedProject{}, false
+}
+
+func archiveExcluded(rel string, excludes []string) bool {
+	return containsString(excludes, filepath.ToSlash(rel))
+}
+
+func addTarFile(tw *tar.Writer, name, path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+	link := ""
+	if info.Mode()&os.ModeSymlink != 0 {
+		if link, err = os.Readlink(path); err != nil {
+			return err
+		}
+	}
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if info.IsDir() {
+		header.Name += "/"
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+func writeProjectArchive(dest, projectPath, dump string, manifest archiveManifest) error {
+	out, err := os.OpenFile(dest, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, secretPerm)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: "manifest.json", Mode: 0644, Size: int64(len(data)), ModTime: manifest.ArchivedAt}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+	if err := addTarFile(tw, "database.sql.gz", dump); err != nil {
+		return err
+	}
+
+	excludes := archiveExcludes(projectPath)
+	err = filepath.Walk(projectPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(projectPath, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if archiveExcluded(rel, excludes) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		return addTarFile(tw, "project/"+filepath.ToSlash(rel), path)
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
+
+func extractProjectArchive(src, projectPath, dump string) (archiveManifest, error) {
+	var manifest archiveManifest
+	f, err := os.Open(src)
+	if err != nil {
+		return manifest, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return manifest, err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, err
+		}
+		switch {
+		case header.Name == "manifest.json":
+			if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+				return manifest, fmt.Errorf("manifest.json: %v", err)
+			}
+			continue
+		case header.Name == "database.sql.gz":
+			if header.Typeflag != tar.TypeReg {
+				return manifest, fmt.Errorf("archive entry %s is not a regular file", header.Name)
+			}
+			if err := extractTarEntry(tr, header, filepath.Dir(dump), dump); err != nil {
+				return manifest, err
+			}
+			continue
+		}
+		rel, ok := strings.CutPrefix(header.Name, "project/")
+		if !ok || rel == "" {
+			continue
+		}
+		target := filepath.Join(projectPath, filepath.FromSlash(rel))
+		if !strings.HasPrefix(target, filepath.Clean(projectPath)+string(os.PathSeparator)) {
+			return manifest, fmt.Errorf("archive entry %s escapes the project directory", header.Name)
+		}
+		if err := extractTarEntry(tr, header, projectPath, target); err != nil {
+			return manifest, err
+		}
+	}
+	return manifest, nil
+}
+
+func resolvedWithin(root, path string) (bool, error) {
+	root, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return false, err
+	}
+	existing, rest := filepath.Clean(path), ""
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			path = filepath.Join(resolved, rest)
+			break
+		}
+		if !os.IsNotExist(err) {
+			return false, err
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return false, err
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+	return path == root || strings.HasPrefix(path, root+string(os.PathSeparator)), nil
+}
+
+func extractTarEntry(tr *tar.Reader, header *tar.Header, root, target string) error {
+	if ok, err := resolvedWithin(root, target); err != nil || !ok {
+		return fmt.Errorf("archive entry %s escapes the project directory", header.Name)
+	}
+	mode := os.FileMode(header.Mode).Perm()
+	switch header.Typeflag {
+	case tar.TypeDir:
+		return os.MkdirAll(target, mode|0700)
+	case tar.TypeSymlink:
+		if filepath.IsAbs(header.Linkname) {
+			return fmt.Errorf("archive entry %s links to an absolute path", header.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		parent, err := filepath.EvalSymlinks(filepath.Dir(target))
+		if err != nil {
+			return err
+		}
+		if ok, err := resolvedWithin(root, filepath.Join(parent, header.Linkname)); err != nil || !ok {
+			return fmt.Errorf("archive entry %s links outside the project directory", header.Name)
+		}
+		return os.Symlink(header.Linkname, target)
+	case tar.TypeReg:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	}
+	return nil
+}
+
+func buildArchiveManifest(projectPath string) archiveManifest {
+	manifest := archiveManifest{
+		Name:       filepath.Base(projectPath),
+		Path:       projectPath,
+		URL:        getSiteURL(projectPath),
+		Excluded:   archiveExcludes(projectPath),
+		ArchivedAt: time.Now().UTC(),
+	}
+	git := func(args ...string) string {
+		out, err := runCommandOutput("git", append([]string{"-C", projectPath}, args...)...)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSpace(out)
+	}
+	manifest.GitRemote = git("remote", "get-url", "origin")
+	manifest.GitCommit = git("rev-parse", "HEAD")
+	if describe, err := ddevDescribe(projectPath); err == nil {
+		manifest.PHPVersion, _ = describe["php_version"].(string)
+		if db, ok := describe["database_type"].(string); ok {
+			manifest.Database = db
+			if version, ok := describe["database_version"].(string); ok {
+				manifest.Database += ":" + version
+			}
+		}
+	}
+	return manifest
+}
+
+func runArchive(args []string) int {
+	fs := flag.NewFlagSet("archive", flag.ContinueOnError)
+	path := fs.String("path", ".", "Project directory to archive")
+	keepLocal := fs.Bool("keep-local", false, "Upload the archive but keep the local project and DDEV containers")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, ".ddev", "config.yaml")); err != nil {
+		printError(fmt.Sprintf("%s is not a DDEV project", projectPath))
+		return 1
+	}
+	settings, err := loadUserSettings()
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	storage, err := settings.Archive.resolve()
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	tmpDir, err := os.MkdirTemp("", "drupal-archive-")
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	defer os.RemoveAll(tmpDir)
+
+	manifest := buildArchiveManifest(projectPath)
+	fileName := fmt.Sprintf("%s-%s.tar.gz", manifest.Name, manifest.ArchivedAt.Format("20060102-150405"))
+	archiveFile := filepath.Join(tmpDir, fileName)
+	dump := filepath.Join(tmpDir, "database.sql.gz")
+	key := storage.key(fileName)
+	record := archivedProject{Name: manifest.Name, Path: projectPath, Bucket: storage.Bucket, Key: key, ArchivedAt: manifest.ArchivedAt}
+
+	steps := []pipelineStep{
+		{name: "export-db", title: "Exporting the database", run: func() error {
+			return runDDEVQuiet(projectPath, "export-db", "--gzip", "--file="+dump)
+		}},
+		{name: "package", title: "Packaging code, files and database", run: func() error {
+			if err := writeProjectArchive(archiveFile, projectPath, dump, manifest); err != nil {
+				return err
+			}
+			info, err := os.Stat(archiveFile)
+			if err != nil {
+				return err
+			}
+			record.Size = info.Size()
+			record.SHA256, err = sha256File(archiveFile)
+			return err
+		}},
+		{name: "upload", title: fmt.Sprintf("Uploading to %s/%s", storage.Bucket, key), run: func() error {
+			if err := storage.upload(key, archiveFile, record.SHA256); err != nil {
+				return err
+			}
+			size, err := storage.size(key)
+			if err != nil {
+				return err
+			}
+			if size != record.Size {
+				return fmt.Errorf("stored object is %d bytes, expected %d", size, record.Size)
+			}
+			archives, err := loadArchives()
+			if err != nil {
+				return err
+			}
+			return saveArchives(append(archives, record))
+		}},
+	}
+	if !*keepLocal {
+		steps = append(steps, pipelineStep{name: "remove", title: "Removing the local project", run: func() error {
+			if err := runDDEVQuiet(projectPath, "delete", "--omit-snapshot", "--yes"); err != nil {
+				return err
+			}
+			if err := os.RemoveAll(projectPath); err != nil {
+				return err
+			}
+			return unregisterProject(projectPath)
+		}})
+	}
+	if err := runSteps(steps); err != nil {
+		return 1
+	}
+
+	printSuccess(fmt.Sprintf("✓ %s archived to %s/%s (%.1f MB)", manifest.Name, storage.Bucket, key, float64(record.Size)/(1<<20)))
+	printStatus(fmt.Sprintf("Restore it with: install-drupal revive %s", manifest.Name))
+	sendWebhooks("archive", "Archived "+manifest.Name, storage.Bucket+"/"+key)
+	return 0
+}
+
+func runRevive(args []string) int {
+	fs := flag.NewFlagSet("revive", flag.ContinueOnError)
+	path := fs.String("path", "", "Directory to restore into (default: the project's original path)")
+	key := fs.String("key", "", "Object key to restore, for archives not listed in ~/.drupal-scripts/archives.json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 && *key == "" {
+		printError("Usage: install-drupal revive [flags] <project-name>")
+		return 2
+	}
+
+	settings, err := loadUserSettings()
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	storage, err := settings.Archive.resolve()
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	record := archivedProject{Key: *key, Bucket: storage.Bucket}
+	if *key == "" {
+		found, ok := findArchive(fs.Arg(0))
+		if !ok {
+			printError(fmt.Sprintf("No archive of %s is recorded; pass --key with the object key", fs.Arg(0)))
+			return 1
+		}
+		record = found
+		storage.Bucket = record.Bucket
+	}
+
+	tmpDir, err := os.MkdirTemp("", "drupal-revive-")
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	defer os.RemoveAll(tmpDir)
+	archiveFile := filepath.Join(tmpDir, filepath.Base(record.Key))
+	dump := filepath.Join(tmpDir, "database.sql.gz")
+
+	projectPath := *path
+	if projectPath == "" {
+		projectPath = record.Path
+	}
+	if projectPath == "" && fs.NArg() == 1 {
+		projectPath = fs.Arg(0)
+	}
+	var manifest archiveManifest
+
+	steps := []pipelineStep{
+		{name: "download", title: fmt.Sprintf("Downloading %s/%s", storage.Bucket, record.Key), run: func() error {
+			if err := storage.download(record.Key, archiveFile); err != nil {
+				return err
+			}
+			if record.SHA256 == "" {
+				return nil
+			}
+			sum, err := sha256File(archiveFile)
+			if err != nil {
+				return err
+			}
+			if sum != record.SHA256 {
+				return fmt.Errorf("checksum mismatch: got %s, expected %s", sum, record.SHA256)
+			}
+			return nil
+		}},
+		{name: "extract", title: "Restoring project files", run: func() error {
+			if projectPath == "" {
+				projectPath = strings.TrimSuffix(filepath.Base(record.Key), ".tar.gz")
+			}
+			abs, err := filepath.Abs(projectPath)
+			if err != nil {
+				return err
+			}
+			projectPath = abs
+			if entries, err := os.ReadDir(projectPath); err == nil && len(entries) > 0 {
+				return fmt.Errorf("%s already exists and is not empty", projectPath)
+			}
+			if err := ensureDir(projectPath); err != nil {
+				return err
+			}
+			manifest, err = extractProjectArchive(archiveFile, projectPath, dump)
+			return err
+		}},
+		{name: "ddev-start", title: "Starting DDEV", run: func() error { return runDDEVQuiet(projectPath, "start") }},
+		{name: "composer", title: "Installing Composer dependencies", run: func() error {
+			return runDDEVQuiet(projectPath, "composer", "install", "--no-interaction")
+		}},
+		{name: "import-db", title: "Importing the database", run: func() error {
+			return runDDEVQuiet(projectPath, "import-db", "--file="+dump)
+		}},
+		{name: "verify", title: "Checking that the site boots", run: func() error {
+			return checkBootstrap(projectPath)
+		}},
+	}
+	if err := runSteps(steps); err != nil {
+		return 1
+	}
+
+	siteURL := getSiteURL(projectPath)
+	registerProject(projectPath, siteURL, nil)
+	printSuccess(fmt.Sprintf("✓ %s revived at %s", filepath.Base(projectPath), siteURL))
+	if manifest.GitCommit != "" {
+		printStatus(fmt.Sprintf("Archived at commit %s (%s)", manifest.GitCommit, manifest.ArchivedAt.Format("2006-01-02")))
+	}
+	sendWebhooks("archive", "Revived "+filepath.Base(projectPath), siteURL)
+	return 0
+}
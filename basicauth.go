@@ -0,0 +1,194 @@
+package main
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+)
+
+const apr1Alphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+type basicAuthSettings struct {
+	Enabled  bool   `yaml:"enabled"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+type basicAuthCredentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+var siteBasicAuth *basicAuthCredentials
+
+func randomString(alphabet string, length int) (string, error) {
+	out := make([]byte, length)
+	max := big.NewInt(int64(len(alphabet)))
+	for i := range out {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		out[i] = alphabet[n.Int64()]
+	}
+	return string(out), nil
+}
+
+func apr1Hash(password, salt string) string {
+	pw := []byte(password)
+	magic := []byte("$apr1$")
+	sl := []byte(salt)
+
+	alt := md5.New()
+	alt.Write(pw)
+	alt.Write(sl)
+	alt.Write(pw)
+	altSum := alt.Sum(nil)
+
+	ctx := md5.New()
+	ctx.Write(pw)
+	ctx.Write(magic)
+	ctx.Write(sl)
+	for i := len(pw); i > 0; i -= 16 {
+		ctx.Write(altSum[:min(i, 16)])
+	}
+	for i := len(pw); i > 0; i >>= 1 {
+		if i&1 != 0 {
+			ctx.Write([]byte{0})
+		} else {
+			ctx.Write(pw[:1])
+		}
+	}
+	final := ctx.Sum(nil)
+
+	for i := 0; i < 1000; i++ {
+		round := md5.New()
+		if i&1 != 0 {
+			round.Write(pw)
+		} else {
+			round.Write(final)
+		}
+		if i%3 != 0 {
+			round.Write(sl)
+		}
+		if i%7 != 0 {
+			round.Write(pw)
+		}
+		if i&1 != 0 {
+			round.Write(final)
+		} else {
+			round.Write(pw)
+		}
+		final = round.Sum(nil)
+	}
+
+	var encoded []byte
+	to64 := func(v uint32, n int) {
+		for ; n > 0; n-- {
+			encoded = append(encoded, apr1Alphabet[v&0x3f])
+			v >>= 6
+		}
+	}
+	groups := [][3]int{{0, 6, 12}, {1, 7, 13}, {2, 8, 14}, {3, 9, 15}, {4, 10, 5}}
+	for _, g := range groups {
+		to64(uint32(final[g[0]])<<16|uint32(final[g[1]])<<8|uint32(final[g[2]]), 4)
+	}
+	to64(uint32(final[11]), 2)
+
+	return string(magic) + salt + "$" + string(encoded)
+}
+
+func basicAuthCredentialsPath(projectName string) (string, error) {
+	dir, err := userConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "credentials", projectName+".json"), nil
+}
+
+func loadOrCreateBasicAuth(projectName string, settings basicAuthSettings) (*basicAuthCredentials, error) {
+	path, err := basicAuthCredentialsPath(projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := &basicAuthCredentials{}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, creds); err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+	}
+
+	if settings.Username != "" {
+		creds.Username = settings.Username
+	}
+	if settings.Password != "" {
+		creds.Password = settings.Password
+	}
+	if creds.Username == "" {
+		creds.Username = "preview"
+	}
+	if creds.Password == "" {
+		creds.Password, err = randomString(apr1Alphabet[2:], 20)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeSecretFile(path, data); err != nil {
+		return nil, err
+	}
+	return creds, nil
+}
+
+func setupBasicAuth(projectPath string, settings basicAuthSettings) error {
+	if !settings.Enabled {
+		return nil
+	}
+	printStatus("Configuring HTTP basic auth...")
+
+	creds, err := loadOrCreateBasicAuth(filepath.Base(projectPath), settings)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to prepare basic auth credentials: %v", err))
+		return err
+	}
+
+	salt, err := randomString(apr1Alphabet, 8)
+	if err != nil {
+		return err
+	}
+	htpasswd := []byte(creds.Username + ":" + apr1Hash(creds.Password, salt) + "\n")
+
+	files := map[string]string{
+		filepath.Join(".ddev", "nginx", ".htpasswd"): string(htpasswd),
+		filepath.Join(".ddev", "nginx", "basic-auth.conf"): "# Managed by drupal-scripts; edit drupal-scripts.yml instead.\n" +
+			"auth_basic \"Restricted\";\n" +
+			"auth_basic_user_file /var/www/html/.ddev/nginx/.htpasswd;\n",
+		filepath.Join(".ddev", "apache", ".htpasswd"): string(htpasswd),
+		filepath.Join(".ddev", "apache", "basic-auth.conf"): "# Managed by drupal-scripts; edit drupal-scripts.yml instead.\n" +
+			"<Location />\n" +
+			"    AuthType Basic\n" +
+			"    AuthName \"Restricted\"\n" +
+			"    AuthUserFile /var/www/html/.ddev/apache/.htpasswd\n" +
+			"    Require valid-user\n" +
+			"</Location>\n",
+	}
+	for rel, content := range files {
+		if err := writeFile(filepath.Join(projectPath, rel), []byte(content)); err != nil {
+			printError(fmt.Sprintf("Failed to write %s", rel))
+			return err
+		}
+	}
+
+	siteBasicAuth = creds
+	printSuccess(fmt.Sprintf("✓ Basic auth enabled (username: %s, password: %s)", creds.Username, creds.Password))
+	return nil
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type benchmarkProvider struct {
+	name    string
+	label   string
+	context string
+}
+
+var benchmarkProviders = []benchmarkProvider{
+	{name: "docker", label: "Docker Desktop", context: "desktop-linux"},
+	{name: "colima", label: "Colima", context: "colima"},
+	{name: "orbstack", label: "OrbStack", context: "orbstack"},
+}
+
+type benchmarkResult struct {
+	provider benchmarkProvider
+	timings  map[string]time.Duration
+	err      error
+}
+
+var benchmarkColumns = []string{"ddev start", "db import", "composer install", "drush cr", "page avg", "page p95"}
+
+func currentDockerContext() string {
+	output, err := exec.Command("docker", "context", "show").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func dockerContextExists(name string) bool {
+	return exec.Command("docker", "context", "inspect", name).Run() == nil
+}
+
+func useDockerContext(name string) error {
+	return exec.Command("docker", "context", "use", name).Run()
+}
+
+func runDDEVQuiet(projectPath string, args ...string) error {
+	cmd := ddevCommand(projectPath, args...)
+	if dryRunCommand(cmd) {
+		return nil
+	}
+	output, err := combinedOutput(cmd)
+	if err != nil {
+		fmt.Print(string(output))
+	}
+	return err
+}
+
+func timeStep(timings map[string]time.Duration, name string, fn func() error) error {
+	started := time.Now()
+	if err := fn(); err != nil {
+		return fmt.Errorf("%s: %v", name, err)
+	}
+	timings[name] = time.Since(started)
+	return nil
+}
+
+func benchmarkRequests(url string, count int) (time.Duration, time.Duration, error) {
+	client := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	var samples []time.Duration
+	for i := 0; i < count; i++ {
+		started := time.Now()
+		resp, err := client.Get(url)
+		if err != nil {
+			return 0, 0, err
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 500 {
+			return 0, 0, fmt.Errorf("GET %s: %s", url, resp.Status)
+		}
+		samples = append(samples, time.Since(started))
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	var total time.Duration
+	for _, s := range samples {
+		total += s
+	}
+	p95 := samples[(len(samples)*95+99)/100-1]
+	return total / time.Duration(len(samples)), p95, nil
+}
+
+func benchmarkProviderRun(projectPath, dumpPath string, provider benchmarkProvider, requests int) benchmarkResult {
+	result := benchmarkResult{provider: provider, timings: map[string]time.Duration{}}
+	printStatus(fmt.Sprintf("Benchmarking %s (docker context %s)...", provider.label, provider.context))
+
+	if err := useDockerContext(provider.context); err != nil {
+		result.err = fmt.Errorf("switch context: %v", err)
+		return result
+	}
+
+	steps := []struct {
+		name string
+		fn   func() error
+	}{
+		{"ddev start", func() error { return runDDEVQuiet(projectPath, "start") }},
+		{"db import", func() error { return runDDEVQuiet(projectPath, "import-db", "--file="+dumpPath) }},
+		{"composer install", func() error {
+			if err := os.RemoveAll(filepath.Join(projectPath, "vendor")); err != nil {
+				return err
+			}
+			return runDDEVQuiet(projectPath, "composer", "install", "--no-interaction")
+		}},
+		{"drush cr", func() error { return runDDEVQuiet(projectPath, "drush", "cr") }},
+	}
+	for _, step := range steps {
+		if err := timeStep(result.timings, step.name, step.fn); err != nil {
+			result.err = err
+			runDDEVQuiet(projectPath, "stop")
+			return result
+		}
+	}
+
+	avg, p95, err := benchmarkRequests(getSiteURL(projectPath), requests)
+	if err != nil {
+		result.err = err
+	} else {
+		result.timings["page avg"] = avg
+		result.timings["page p95"] = p95
+	}
+
+	runDDEVQuiet(projectPath, "stop")
+	return result
+}
+
+func printBenchmarkTable(results []benchmarkResult) {
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Provider\t%s\t\n", strings.Join(benchmarkColumns, "\t"))
+	for _, r := range results {
+		cells := []string{r.provider.label}
+		for _, col := range benchmarkColumns {
+			d, ok := r.timings[col]
+			switch {
+			case !ok:
+				cells = append(cells, "-")
+			case strings.HasPrefix(col, "page"):
+				cells = append(cells, fmt.Sprintf("%dms", d.Milliseconds()))
+			default:
+				cells = append(cells, formatDuration(d))
+			}
+		}
+		fmt.Fprintf(w, "%s\t\n", strings.Join(cells, "\t"))
+	}
+	w.Flush()
+
+	for _, r := range results {
+		if r.err != nil {
+			printWarning(fmt.Sprintf("%s: %v", r.provider.label, r.err))
+		}
+	}
+}
+
+func runBenchmark(args []string) int {
+	fs := flag.NewFlagSet("benchmark", flag.ContinueOnError)
+	path := fs.String("path", ".", "Existing DDEV project to benchmark")
+	providers := fs.String("providers", "docker,colima,orbstack", "Comma-separated providers to compare")
+	requests := fs.Int("requests", 20, "Number of anonymous page requests per provider")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, ".ddev", "config.yaml")); err != nil {
+		printError(fmt.Sprintf("%s is not a DDEV project", projectPath))
+		return 1
+	}
+	if *requests < 1 {
+		printError("--requests must be at least 1")
+		return 2
+	}
+
+	wanted := map[string]bool{}
+	for _, name := range strings.Split(*providers, ",") {
+		wanted[strings.TrimSpace(name)] = true
+	}
+
+	var selected []benchmarkProvider
+	for _, p := range benchmarkProviders {
+		if !wanted[p.name] {
+			continue
+		}
+		if !dockerContextExists(p.context) {
+			printWarning(fmt.Sprintf("Skipping %s: docker context '%s' not found", p.label, p.context))
+			continue
+		}
+		selected = append(selected, p)
+	}
+	if len(selected) == 0 {
+		printError("No providers available to benchmark")
+		return 1
+	}
+
+	originalContext := currentDockerContext()
+	printWarning("The benchmark restarts the project on each provider and reinstalls vendor/.")
+
+	dump, err := os.CreateTemp("", "drupal-benchmark-*.sql.gz")
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	dump.Close()
+	defer os.Remove(dump.Name())
+
+	printStatus("Exporting database snapshot from the current provider...")
+	if err := runDDEVQuiet(projectPath, "export-db", "--file="+dump.Name()); err != nil {
+		printError("Failed to export the database; is the project running?")
+		return 1
+	}
+	if err := runDDEVQuiet(projectPath, "stop"); err != nil {
+		printError("Failed to stop the project")
+		return 1
+	}
+
+	var results []benchmarkResult
+	for _, p := range selected {
+		results = append(results, benchmarkProviderRun(projectPath, dump.Name(), p, *requests))
+	}
+
+	if originalContext != "" {
+		printStatus(fmt.Sprintf("Restoring docker context %s...", originalContext))
+		useDockerContext(originalContext)
+		if err := runDDEVQuiet(projectPath, "start"); err != nil {
+			printWarning("Could not restart the project on the original provider")
+		}
+	}
+
+	printBenchmarkTable(results)
+	return 0
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed ddev/blt/blt
+var bltDDEVCommand []byte
+
+type bltFile struct {
+	Project struct {
+		MachineName string `yaml:"machine_name"`
+		Local       struct {
+			Hostname string `yaml:"hostname"`
+		} `yaml:"local"`
+	} `yaml:"project"`
+}
+
+const bltLocalSettings = `<?php
+
+// Written by drupal-scripts convert: DDEV database for BLT's settings chain.
+$databases['default']['default'] = [
+  'database' => 'db',
+  'username' => 'db',
+  'password' => 'db',
+  'host' => 'db',
+  'port' => 3306,
+  'driver' => 'mysql',
+  'prefix' => '',
+];
+$settings['trusted_host_patterns'][] = '\.ddev\.site$';
+`
+
+func isBLTProject(projectPath string) bool {
+	_, err := os.Stat(filepath.Join(projectPath, "blt", "blt.yml"))
+	return err == nil
+}
+
+func readBLTProject(projectPath string) (*convertedProject, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "blt", "blt.yml"))
+	if err != nil {
+		return nil, err
+	}
+	var blt bltFile
+	if err := yamlUnmarshal(data, &blt); err != nil {
+		return nil, fmt.Errorf("blt/blt.yml: %v", err)
+	}
+
+	c := &convertedProject{
+		source:              "Acquia BLT",
+		name:                blt.Project.MachineName,
+		projectType:         detectDrupalProjectType(projectPath),
+		docroot:             detectDocroot(projectPath),
+		disableSettingsMgmt: true,
+	}
+	if c.name == "" {
+		c.name = filepath.Base(projectPath)
+	}
+	c.name = strings.ReplaceAll(strings.ToLower(c.name), "_", "-")
+	if host := blt.Project.Local.Hostname; host != "" && !strings.Contains(host, "${") {
+		c.addHostname(host, "ddev.site")
+	}
+	c.warnings = append(c.warnings,
+		"BLT keeps control of settings.php; DDEV settings management is disabled and the database is set in sites/default/settings/local.settings.php",
+		"Acquia Cloud hooks and BLT's Lando/DrupalVM setup are not used by DDEV; run BLT commands with 'ddev blt'",
+	)
+	return c, nil
+}
+
+func setUpBLTProject(projectPath, docroot string) error {
+	local := filepath.Join(projectPath, docroot, "sites", "default", "settings", "local.settings.php")
+	if _, err := os.Stat(local); err == nil {
+		printWarning(fmt.Sprintf("Keeping existing %s; point its database at host 'db' (user, password and database 'db')", local))
+	} else if err := writeSecretFile(local, []byte(bltLocalSettings)); err != nil {
+		return err
+	}
+
+	command := filepath.Join(projectPath, ".ddev", "commands", "web", "blt")
+	if _, err := os.Stat(command); err == nil {
+		return nil
+	}
+	if err := writeExecutable(command, bltDDEVCommand); err != nil {
+		return err
+	}
+	printSuccess("✓ Added 'ddev blt' (setup, drupal:update, config import/export, tests and phpcs map to drush and composer)")
+	return nil
+}
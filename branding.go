@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var brandColorPattern = regexp.MustCompile(`^#?([0-9a-fA-F]{6}|[0-9a-fA-F]{3})$`)
+
+var faviconSizes = []int{16, 32, 48}
+
+const appleTouchIconSize = 180
+
+const coreMailSignature = "--  [site:name] team"
+
+type branding struct {
+	logo        string
+	logoImage   image.Image
+	color       string
+	emailFooter string
+}
+
+func (b branding) empty() bool {
+	return b.logo == "" && b.color == "" && b.emailFooter == ""
+}
+
+var siteBranding branding
+
+func loadBranding() error {
+	b := branding{logo: opts.logo, emailFooter: strings.TrimSpace(opts.emailFooter)}
+	if opts.brandColor != "" {
+		m := brandColorPattern.FindStringSubmatch(opts.brandColor)
+		if m == nil {
+			return fmt.Errorf("invalid --brand-color %q (expected a hex color such as #1a73e8)", opts.brandColor)
+		}
+		hex := strings.ToLower(m[1])
+		if len(hex) == 3 {
+			hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+		}
+		b.color = "#" + hex
+	}
+	if b.logo != "" {
+		data, err := os.ReadFile(b.logo)
+		if err != nil {
+			return fmt.Errorf("cannot read --logo: %v", err)
+		}
+		if strings.ToLower(filepath.Ext(b.logo)) != ".svg" {
+			img, _, err := image.Decode(bytes.NewReader(data))
+			if err != nil {
+				return fmt.Errorf("--logo %s is not a PNG, JPEG, GIF or SVG image: %v", b.logo, err)
+			}
+			b.logoImage = img
+		}
+	}
+	siteBranding = b
+	return nil
+}
+
+func squareIcon(img image.Image, size int) *image.NRGBA {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	scale := float64(size) / float64(max(w, h))
+	dw, dh := max(1, int(float64(w)*scale+0.5)), max(1, int(float64(h)*scale+0.5))
+	offX, offY := (size-dw)/2, (size-dh)/2
+
+	icon := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < dh; y++ {
+		sy0 := bounds.Min.Y + y*h/dh
+		sy1 := max(sy0+1, bounds.Min.Y+(y+1)*h/dh)
+		for x := 0; x < dw; x++ {
+			sx0 := bounds.Min.X + x*w/dw
+			sx1 := max(sx0+1, bounds.Min.X+(x+1)*w/dw)
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					pr, pg, pb, pa := img.At(sx, sy).RGBA()
+					r, g, b, a, n = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa), n+1
+				}
+			}
+			avg := color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)}
+			icon.Set(offX+x, offY+y, avg)
+		}
+	}
+	return icon
+}
+
+func touchIcon(img image.Image, brandColor string) *image.NRGBA {
+	background := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	if brandColor != "" {
+		var r, g, b uint8
+		fmt.Sscanf(brandColor, "#%02x%02x%02x", &r, &g, &b)
+		background = color.NRGBA{R: r, G: g, B: b, A: 255}
+	}
+	icon := image.NewNRGBA(image.Rect(0, 0, appleTouchIconSize, appleTouchIconSize))
+	draw.Draw(icon, icon.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+	draw.Draw(icon, icon.Bounds(), squareIcon(img, appleTouchIconSize), image.Point{}, draw.Over)
+	return icon
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeICO(img image.Image, sizes []int) ([]byte, error) {
+	var images [][]byte
+	for _, size := range sizes {
+		data, err := encodePNG(squareIcon(img, size))
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, data)
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, [3]uint16{0, 1, uint16(len(images))})
+	offset := 6 + 16*len(images)
+	for i, data := range images {
+		dim := uint8(sizes[i] % 256)
+		binary.Write(&buf, binary.LittleEndian, struct {
+			Width, Height, Colors, Reserved uint8
+			Planes, BitCount                uint16
+			Size, Offset                    uint32
+		}{dim, dim, 0, 0, 1, 32, uint32(len(data)), uint32(offset)})
+		offset += len(data)
+	}
+	for _, data := range images {
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+func defaultTheme(projectPath string) (string, error) {
+	output, err := ddevOutput(projectPath, "drush", "config:get", "system.theme", "default", "--format=json")
+	if err != nil {
+		return "", err
+	}
+	var values map[string]string
+	if err := json.Unmarshal(output, &values); err != nil {
+		return "", err
+	}
+	theme := values["system.theme:default"]
+	if theme == "" {
+		return "", fmt.Errorf("system.theme has no default theme")
+	}
+	return theme, nil
+}
+
+func writeBrandingFiles(projectPath string) (string, error) {
+	docroot := filepath.Join(projectPath, projectDocroot(projectPath))
+	logo := "branding/logo" + strings.ToLower(filepath.Ext(siteBranding.logo))
+	data, err := os.ReadFile(siteBranding.logo)
+	if err != nil {
+		return "", err
+	}
+	if err := writeFile(filepath.Join(docroot, filepath.FromSlash(logo)), data); err != nil {
+		return "", err
+	}
+	if siteBranding.logoImage == nil {
+		printWarning("No favicons generated from an SVG logo; pass a PNG logo to get favicon.ico and apple-touch-icon.png")
+		return logo, nil
+	}
+
+	ico, err := encodeICO(siteBranding.logoImage, faviconSizes)
+	if err != nil {
+		return "", err
+	}
+	if err := writeFile(filepath.Join(docroot, "favicon.ico"), ico); err != nil {
+		return "", err
+	}
+	touch, err := encodePNG(touchIcon(siteBranding.logoImage, siteBranding.color))
+	if err != nil {
+		return "", err
+	}
+	if err := writeFile(filepath.Join(docroot, "apple-touch-icon.png"), touch); err != nil {
+		return "", err
+	}
+	return logo, nil
+}
+
+func brandedMailBodies(projectPath, footer string) ([][2]string, error) {
+	output, err := ddevOutput(projectPath, "drush", "config:get", "user.mail", "--format=json")
+	if err != nil {
+		return nil, err
+	}
+	var mails map[string]any
+	if err := json.Unmarshal(output, &mails); err != nil {
+		return nil, err
+	}
+	var bodies [][2]string
+	for _, key := range sortedKeys(mails) {
+		mail, ok := mails[key].(map[string]any)
+		if !ok {
+			continue
+		}
+		body, ok := mail["body"].(string)
+		if !ok {
+			continue
+		}
+		body = strings.TrimRight(body, "\n ")
+		body = strings.TrimRight(strings.TrimSuffix(body, coreMailSignature), "\n ")
+		bodies = append(bodies, [2]string{key + ".body", body + "\n\n-- \n" + footer})
+	}
+	return bodies, nil
+}
+
+func applyBranding(projectPath string) error {
+	printStatus("Applying site branding...")
+
+	var settings []configSetting
+	if siteBranding.logo != "" || siteBranding.color != "" {
+		theme, err := defaultTheme(projectPath)
+		if err != nil {
+			printError(fmt.Sprintf("Could not read the default theme: %v", err))
+			return err
+		}
+		themeSettings := configSetting{name: theme + ".settings"}
+		if siteBranding.logo != "" {
+			logo, err := writeBrandingFiles(projectPath)
+			if err != nil {
+				printError(fmt.Sprintf("Failed to write the logo and favicons: %v", err))
+				return err
+			}
+			themeSettings.values = append(themeSettings.values,
+				[2]string{"logo.use_default", "0"},
+				[2]string{"logo.path", logo})
+			if siteBranding.logoImage != nil {
+				themeSettings.values = append(themeSettings.values,
+					[2]string{"favicon.use_default", "0"},
+					[2]string{"favicon.path", "favicon.ico"},
+					[2]string{"favicon.mimetype", "image/vnd.microsoft.icon"})
+			}
+		}
+		if siteBranding.color != "" {
+			if theme == "olivero" {
+				themeSettings.values = append(themeSettings.values, [2]string{"base_primary_color", siteBranding.color})
+			} else {
+				printWarning(fmt.Sprintf("--brand-color only sets Olivero's primary color; set it in the %s theme yourself", theme))
+			}
+		}
+		if len(themeSettings.values) > 0 {
+			settings = append(settings, themeSettings)
+		}
+	}
+	if siteBranding.emailFooter != "" {
+		bodies, err := brandedMailBodies(projectPath, siteBranding.emailFooter)
+		if err != nil {
+			printError(fmt.Sprintf("Could not read the account emails: %v", err))
+			return err
+		}
+		settings = append(settings, configSetting{name: "user.mail", values: bodies})
+	}
+
+	if err := applyConfigSettings(projectPath, settings); err != nil {
+		return err
+	}
+	printSuccess("✓ Site branding applied")
+	return nil
+}
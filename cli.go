@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+type stringListFlag []string
+
+func (f *stringListFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*f = append(*f, item)
+		}
+	}
+	return nil
+}
+
+type keyValueFlag map[string]string
+
+func (f keyValueFlag) String() string {
+	pairs := make([]string, 0, len(f))
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f keyValueFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(key) == "" {
+		return fmt.Errorf("expected KEY=VALUE, got %q", value)
+	}
+	f[strings.TrimSpace(key)] = val
+	return nil
+}
+
+type optionalBool struct {
+	set   bool
+	value bool
+}
+
+func (b *optionalBool) String() string {
+	return strconv.FormatBool(b.value)
+}
+
+func (b *optionalBool) Set(value string) error {
+	v, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	b.set, b.value = true, v
+	return nil
+}
+
+func (b *optionalBool) IsBoolFlag() bool {
+	return true
+}
+
+type options struct {
+	projectName      string
+	path             string
+	dockerProvider   string
+	profile          string
+	phpVersion       string
+	configVars       keyValueFlag
+	policyURL        string
+	policySHA256     string
+	configFile       string
+	basicAuth        bool
+	basicAuthUser    string
+	notify           bool
+	upgradeDDEV      bool
+	quick            bool
+	demo             bool
+	harden           bool
+	adminPassword    string
+	siteName         string
+	logo             string
+	brandColor       string
+	emailFooter      string
+	generateContent  optionalBool
+	nonInteractive   bool
+	dryRun           bool
+	verbose          int
+	quiet            bool
+	logFile          string
+	output           string
+	resume           string
+	skipContent      bool
+	skipModules      bool
+	skipConfigImport bool
+	skipDeps         bool
+	recordAnswers    string
+	answersFile      string
+	presets          stringListFlag
+	ide              stringListFlag
+	constraints      string
+	composerLockOnly bool
+	fromLock         string
+	docroot          string
+	projectType      string
+	dbUI             string
+	cron             string
+	timezone         string
+	country          string
+	firstDay         string
+	existingConfig   string
+	ifExists         string
+	sharedDomain     string
+	sharedRoot       string
+	noColor          bool
+	ascii            bool
+	interactive      bool
+	flagSet          map[string]bool
+}
+
+var opts options
+
+var stdinReader = bufio.NewReader(os.Stdin)
+
+func stdinIsTerminal() bool {
+	return isTerminal(os.Stdin)
+}
+
+func parseFlags(args []string) error {
+	fs := flag.NewFlagSet("install-drupal", flag.ContinueOnError)
+	fs.StringVar(&opts.projectName, "project-name", "", "Name of the Drupal project directory to create")
+	fs.StringVar(&opts.path, "path", "", "Directory to create the project in (default: workspace from config.yml, else the current directory)")
+	fs.StringVar(&opts.dockerProvider, "docker-provider", "", "Docker provider to use: docker or colima")
+	fs.StringVar(&opts.profile, "profile", "", "Apply a named profile from ~/.drupal-scripts/config.yml on top of its defaults")
+	fs.StringVar(&opts.phpVersion, "php-version", "", "PHP version for DDEV, e.g. 8.3 (default: ddev.php_version, then the user defaults)")
+	fs.StringVar(&opts.configFile, "config", "", "Path to a drupal-scripts.yml project configuration file")
+	fs.StringVar(&opts.adminPassword, "admin-password", "admin", "Password for the Drupal admin account")
+	fs.StringVar(&opts.adminPassword, "admin-pass", "admin", "Alias for --admin-password")
+	fs.StringVar(&opts.siteName, "site-name", "Super Awesome Site", "Name of the Drupal site")
+	fs.StringVar(&opts.logo, "logo", "", "Logo file (PNG, JPEG, GIF or SVG) for the default theme; raster logos also give the favicons")
+	fs.StringVar(&opts.brandColor, "brand-color", "", "Brand color as a hex value, e.g. #1a73e8 (Olivero's primary color)")
+	fs.StringVar(&opts.emailFooter, "email-footer", "", "Footer for account emails, replacing the '[site:name] team' signature")
+	fs.Var(&opts.generateContent, "generate-content", "Generate sample users and content without asking (--generate-content=false to skip)")
+	fs.StringVar(&opts.recordAnswers, "record-answers", "", "Write this run's answers to a JSON file for --answers")
+	fs.StringVar(&opts.answersFile, "answers", "", "Replay the answers recorded with --record-answers (flags still take precedence)")
+	fs.BoolVar(&opts.nonInteractive, "non-interactive", false, "Never prompt, even on a terminal; unanswered questions use their defaults")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "Print every command and file change instead of running it")
+	fs.Var(&verbosityFlag{verbose: &opts.verbose, level: 1}, "v", "Print each command before running it")
+	fs.Var(&verbosityFlag{verbose: &opts.verbose, level: 2}, "vv", "Also print the output of commands whose output is normally hidden")
+	fs.StringVar(&opts.output, "output", outputText, "Output format: text, or json for one JSON progress event per line on stdout")
+	fs.BoolVar(&opts.quiet, "quiet", false, "Hide command output behind a spinner and show only step lines, warnings and errors")
+	fs.StringVar(&opts.logFile, "log-file", "", "Append a transcript of messages, commands and their full output to this file")
+	fs.BoolVar(&opts.skipDeps, "skip-deps", false, "Skip the Composer dependencies step (composer install and the module packages)")
+	fs.BoolVar(&opts.skipModules, "skip-modules", false, "Skip enabling modules, and the presets and config_ignore steps that need them")
+	fs.BoolVar(&opts.skipConfigImport, "skip-config-import", false, "Skip importing config/sync after the site is installed")
+	fs.BoolVar(&opts.skipContent, "skip-content", false, "Skip sample content generation")
+	fs.StringVar(&opts.resume, "resume", "", "Continue the unfinished install in this project directory, skipping completed steps")
+	fs.StringVar(&opts.policyURL, "policy-url", "", "URL or file path of an organization policy to enforce")
+	fs.StringVar(&opts.policySHA256, "policy-sha256", "", "Expected sha256 of a policy fetched from --policy-url")
+	fs.BoolVar(&opts.basicAuth, "basic-auth", false, "Protect the site with HTTP basic auth (credentials are generated and stored)")
+	fs.StringVar(&opts.basicAuthUser, "basic-auth-user", "", "Username for HTTP basic auth (default: preview)")
+	fs.BoolVar(&opts.notify, "notify", true, "Send a desktop notification when the install finishes, fails or needs input")
+	fs.BoolVar(&opts.upgradeDDEV, "upgrade-ddev", false, "Upgrade DDEV without asking when it is older than the supported minimum")
+	fs.BoolVar(&opts.quick, "quick", false, "Minimal install: skip dev packages, seed config import and content generation")
+	fs.BoolVar(&opts.demo, "demo", false, "Demo install: Umami profile, rich generated content, all presets and a URL tour")
+	fs.BoolVar(&opts.harden, "harden", false, "Apply a security baseline (same as --preset harden)")
+	fs.Var(&opts.presets, "preset", "Enable a preset (repeatable or comma-separated)")
+	fs.Var(&opts.ide, "ide", "Editor settings to generate: vscode, phpstorm or none (repeatable or comma-separated; default both)")
+	fs.StringVar(&opts.constraints, "constraints", "", "How added drupal/* packages are constrained: caret or exact (default caret)")
+	fs.BoolVar(&opts.composerLockOnly, "composer-lock-only", false, "Require packages updating composer.lock only, then install everything in one pass")
+	fs.StringVar(&opts.fromLock, "from-lock", "", "Install the exact dependency set of a saved lock snapshot (name, directory or composer.lock path)")
+	opts.configVars = keyValueFlag{}
+	fs.Var(opts.configVars, "config-var", "Set a config template variable as KEY=VALUE (repeatable)")
+	fs.StringVar(&opts.docroot, "docroot", "", "DDEV docroot (default: detected from composer.json or web/, docroot/, html/)")
+	fs.StringVar(&opts.projectType, "project-type", "", "DDEV project type (default: detected from the drupal/core version, e.g. drupal11)")
+	fs.StringVar(&opts.dbUI, "db-ui", "", "Install a database UI add-on: phpmyadmin or adminer")
+	fs.StringVar(&opts.cron, "cron", "", "How often DDEV runs drush cron, e.g. 5m or 1h, or off (default 10m)")
+	fs.StringVar(&opts.timezone, "timezone", "", "Site default timezone, e.g. Europe/Berlin (default: this machine's)")
+	fs.StringVar(&opts.country, "country", "", "Site default country as a two-letter code (default: from LANG)")
+	fs.StringVar(&opts.firstDay, "first-day", "", "First day of the week: a weekday name or 0-6 (default: from the country)")
+	fs.StringVar(&opts.existingConfig, "existing-config", "", "Install from a full config export in this directory (site:install --existing-config)")
+	fs.StringVar(&opts.ifExists, "if-exists", "", "When the project directory is not empty: abort, or adopt the Drupal project there (default: ask, or abort)")
+	fs.StringVar(&opts.sharedDomain, "shared-domain", "", "Shared server mode: wildcard DNS domain sites are served under")
+	fs.StringVar(&opts.sharedRoot, "shared-root", "", "Shared server mode: directory holding per-user project namespaces (default /srv/drupal)")
+	fs.BoolVar(&opts.noColor, "no-color", false, "Disable ANSI colors in output (also honors NO_COLOR)")
+	fs.BoolVar(&opts.ascii, "ascii", false, "Use plain ASCII markers instead of ✓/✗ glyphs")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	opts.flagSet = map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		opts.flagSet[f.Name] = true
+	})
+	if resumeAnswers != nil {
+		if err := applyAnswers(fs, installStateFile, resumeAnswers); err != nil {
+			printError(fmt.Sprintf("Failed to load the install state: %v", err))
+			return err
+		}
+	}
+	if opts.answersFile != "" {
+		if err := applyAnswersFile(fs, opts.answersFile); err != nil {
+			printError(fmt.Sprintf("Failed to load answers: %v", err))
+			return err
+		}
+		printStatus(fmt.Sprintf("Replaying answers from %s", opts.answersFile))
+	}
+	recordFlagAnswers(fs)
+
+	if opts.noColor {
+		useColor = false
+	}
+	asciiOnly = opts.ascii
+
+	opts.dockerProvider = strings.ToLower(strings.TrimSpace(opts.dockerProvider))
+	if opts.dockerProvider != "" && opts.dockerProvider != "docker" && opts.dockerProvider != "colima" {
+		printError(fmt.Sprintf("Invalid --docker-provider %q (expected docker or colima)", opts.dockerProvider))
+		return fmt.Errorf("invalid docker provider")
+	}
+
+	opts.ifExists = strings.ToLower(strings.TrimSpace(opts.ifExists))
+	if opts.ifExists != "" && opts.ifExists != ifExistsAbort && opts.ifExists != ifExistsAdopt {
+		printError(fmt.Sprintf("Invalid --if-exists %q (expected abort or adopt)", opts.ifExists))
+		return fmt.Errorf("invalid --if-exists")
+	}
+
+	opts.output = strings.ToLower(strings.TrimSpace(opts.output))
+	switch opts.output {
+	case outputText:
+	case outputJSON:
+		startJSONOutput()
+	default:
+		printError(fmt.Sprintf("Invalid --output %q (expected text or json)", opts.output))
+		return fmt.Errorf("invalid --output")
+	}
+
+	opts.interactive = stdinIsTerminal() && !opts.nonInteractive && !jsonOutput()
+	return nil
+}
+
+func requireNonInteractiveFlags() error {
+	if opts.interactive {
+		return nil
+	}
+
+	var missing []string
+	if opts.projectName == "" {
+		missing = append(missing, "--project-name <name>")
+	}
+	reason := "stdin is not a terminal"
+	if opts.nonInteractive {
+		reason = "--non-interactive is set"
+	} else if jsonOutput() {
+		reason = "--output=json is set"
+	}
+	if len(missing) == 0 {
+		printStatus(reason + "; running non-interactively with defaults")
+		return nil
+	}
+
+	printError(reason + ", so prompts cannot be answered.")
+	fmt.Println("Re-run with the following required flags:")
+	for _, f := range missing {
+		fmt.Printf("  %s\n", f)
+	}
+	fmt.Println("Optional flags:")
+	fmt.Println("  --docker-provider docker|colima  (default: recommended for this machine)")
+	fmt.Println("  --site-name <name>               (default: Super Awesome Site)")
+	fmt.Println("  --admin-pass <password>          (default: admin)")
+	fmt.Println("  --generate-content[=false]       (default: false)")
+	fmt.Println("  --timezone, --country, --first-day  (default: this machine's)")
+	fmt.Println("  --if-exists abort|adopt          (default: abort)")
+	return fmt.Errorf("missing required flags for non-interactive run")
+}
+
+func promptLine(question string) string {
+	fmt.Print(question)
+	response, _ := stdinReader.ReadString('\n')
+	return strings.TrimSpace(response)
+}
+
+func promptKey(question string) string {
+	saved, err := sttyOutput("-g")
+	if err != nil {
+		return promptLine(question)
+	}
+	if _, err := sttyOutput("-icanon", "-echo", "min", "1"); err != nil {
+		return promptLine(question)
+	}
+	defer sttyOutput(strings.TrimSpace(saved))
+	fmt.Print(question)
+	key, err := stdinReader.ReadByte()
+	fmt.Println()
+	if err != nil {
+		return ""
+	}
+	return string(key)
+}
+
+func sttyOutput(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	output, err := cmd.Output()
+	return string(output), err
+}
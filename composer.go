@@ -0,0 +1,317 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type jsonObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func newJSONObject() *jsonObject {
+	return &jsonObject{values: map[string]any{}}
+}
+
+func (o *jsonObject) set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+func (o *jsonObject) prepend(key string, value any) {
+	if _, ok := o.values[key]; ok {
+		o.values[key] = value
+		return
+	}
+	o.keys = append([]string{key}, o.keys...)
+	o.values[key] = value
+}
+
+func (o *jsonObject) object(key string) *jsonObject {
+	if v, ok := o.values[key].(*jsonObject); ok {
+		return v
+	}
+	child := newJSONObject()
+	o.set(key, child)
+	return child
+}
+
+func (o *jsonObject) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	value, err := decodeJSONValue(dec)
+	if err != nil {
+		return err
+	}
+	obj, ok := value.(*jsonObject)
+	if !ok {
+		return fmt.Errorf("expected a JSON object")
+	}
+	*o = *obj
+	return nil
+}
+
+func decodeJSONValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := newJSONObject()
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj.set(keyTok.(string), value)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return obj, nil
+		case '[':
+			list := []any{}
+			for dec.More() {
+				value, err := decodeJSONValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return list, nil
+		}
+	}
+	return tok, nil
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := writeJSONValue(&buf, key); err != nil {
+			return nil, err
+		}
+		buf.WriteByte(':')
+		if err := writeJSONValue(&buf, o.values[key]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func writeJSONValue(w io.Writer, value any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return enc.Encode(value)
+}
+
+func (o *jsonObject) unmarshalYAMLNode(node any) error {
+	value := jsonFromYAML(node)
+	obj, ok := value.(*jsonObject)
+	if !ok {
+		return fmt.Errorf("expected a mapping")
+	}
+	*o = *obj
+	return nil
+}
+
+func jsonFromYAML(node any) any {
+	switch n := node.(type) {
+	case *yamlMap:
+		obj := newJSONObject()
+		for _, k := range n.keys {
+			obj.set(k, jsonFromYAML(n.values[k]))
+		}
+		return obj
+	case []any:
+		list := make([]any, len(n))
+		for i, v := range n {
+			list[i] = jsonFromYAML(v)
+		}
+		return list
+	}
+	return yamlNatural(node)
+}
+
+func readJSONFile(path string) (*jsonObject, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	obj := newJSONObject()
+	if err := json.Unmarshal(data, obj); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return obj, nil
+}
+
+func writeJSONFile(path string, obj *jsonObject) error {
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(obj); err != nil {
+		return err
+	}
+	return writeFile(path, out.Bytes())
+}
+
+type composerPatch struct {
+	Repositories   []jsonObject `yaml:"repositories"`
+	Scripts        jsonObject   `yaml:"scripts"`
+	Patches        jsonObject   `yaml:"patches"`
+	InstallerPaths jsonObject   `yaml:"installer-paths"`
+	AllowPlugins   jsonObject   `yaml:"allow-plugins"`
+	Extra          jsonObject   `yaml:"extra"`
+	Constraints    string       `yaml:"constraints"`
+	LockOnly       bool         `yaml:"lock_only"`
+	FromLock       string       `yaml:"from_lock"`
+}
+
+func (p composerPatch) empty() bool {
+	return len(p.Repositories) == 0 && len(p.Scripts.keys) == 0 && len(p.Patches.keys) == 0 &&
+		len(p.InstallerPaths.keys) == 0 && len(p.AllowPlugins.keys) == 0 && len(p.Extra.keys) == 0
+}
+
+func mergeJSONObject(dst, src *jsonObject) {
+	for _, key := range src.keys {
+		srcValue := src.values[key]
+		if srcObj, ok := srcValue.(*jsonObject); ok {
+			if dstObj, ok := dst.values[key].(*jsonObject); ok {
+				mergeJSONObject(dstObj, srcObj)
+				continue
+			}
+		}
+		dst.set(key, srcValue)
+	}
+}
+
+func repositoryKey(repo any) string {
+	if obj, ok := repo.(*jsonObject); ok {
+		if url, ok := obj.values["url"].(string); ok {
+			return strings.TrimSuffix(url, "/")
+		}
+	}
+	data, _ := json.Marshal(repo)
+	return string(data)
+}
+
+func addRepositories(composer *jsonObject, repos []jsonObject) {
+	if named, ok := composer.values["repositories"].(*jsonObject); ok {
+		seen := map[string]bool{}
+		for _, key := range named.keys {
+			seen[repositoryKey(named.values[key])] = true
+		}
+		for i := range repos {
+			repo := &repos[i]
+			key := repositoryKey(repo)
+			if !seen[key] {
+				named.set(key, repo)
+				seen[key] = true
+			}
+		}
+		return
+	}
+
+	existing, _ := composer.values["repositories"].([]any)
+	seen := map[string]bool{}
+	for _, repo := range existing {
+		seen[repositoryKey(repo)] = true
+	}
+	for i := range repos {
+		repo := &repos[i]
+		if !seen[repositoryKey(repo)] {
+			existing = append(existing, repo)
+			seen[repositoryKey(repo)] = true
+		}
+	}
+	composer.set("repositories", existing)
+}
+
+func applyComposerPatch(projectPath string, patch composerPatch) error {
+	if patch.empty() {
+		return nil
+	}
+	printStatus("Patching composer.json...")
+
+	composerPath := filepath.Join(projectPath, "composer.json")
+	composer, err := readJSONFile(composerPath)
+	if err != nil {
+		printError("Failed to read composer.json")
+		return err
+	}
+
+	if len(patch.Repositories) > 0 {
+		addRepositories(composer, patch.Repositories)
+	}
+
+	if len(patch.Scripts.keys) > 0 {
+		mergeJSONObject(composer.object("scripts"), &patch.Scripts)
+	}
+
+	if len(patch.AllowPlugins.keys) > 0 {
+		mergeJSONObject(composer.object("config").object("allow-plugins"), &patch.AllowPlugins)
+	}
+
+	extra := composer.object("extra")
+	if len(patch.Extra.keys) > 0 {
+		mergeJSONObject(extra, &patch.Extra)
+	}
+
+	if len(patch.InstallerPaths.keys) > 0 {
+		paths := extra.object("installer-paths")
+		for i := len(patch.InstallerPaths.keys) - 1; i >= 0; i-- {
+			key := patch.InstallerPaths.keys[i]
+			paths.prepend(key, patch.InstallerPaths.values[key])
+		}
+	}
+
+	if len(patch.Patches.keys) > 0 {
+		mergeJSONObject(extra.object("patches"), &patch.Patches)
+		composer.object("config").object("allow-plugins").set("cweagans/composer-patches", true)
+		if !composerRequires(composer, "cweagans/composer-patches") {
+			composerPackages = append([]string{"cweagans/composer-patches"}, composerPackages...)
+		}
+	}
+
+	if err := writeJSONFile(composerPath, composer); err != nil {
+		printError("Failed to write composer.json")
+		return err
+	}
+
+	printSuccess("✓ composer.json patched")
+	return nil
+}
+
+func composerRequires(composer *jsonObject, pkg string) bool {
+	for _, section := range []string{"require", "require-dev"} {
+		if obj, ok := composer.values[section].(*jsonObject); ok {
+			if _, ok := obj.values[pkg]; ok {
+				return true
+			}
+		}
+	}
+	return containsPackage(composerPackages, pkg)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type composerScript struct {
+	name    string
+	command string
+}
+
+func qualityScripts(docroot string) []composerScript {
+	standards := "--standard=Drupal,DrupalPractice --extensions=php,module,inc,install,test,profile,theme,css,info,txt,md,yml --ignore=node_modules,vendor"
+	paths := docroot + "/modules/custom " + docroot + "/themes/custom"
+	return []composerScript{
+		{name: "phpcs", command: "phpcs " + standards + " " + paths},
+		{name: "phpcbf", command: "phpcbf " + standards + " " + paths},
+	}
+}
+
+func appendComposerScript(scripts *jsonObject, name, command string) bool {
+	var commands []any
+	switch existing := scripts.values[name].(type) {
+	case string:
+		commands = []any{existing}
+	case []any:
+		commands = existing
+	}
+	for _, c := range commands {
+		if c == command {
+			return false
+		}
+	}
+	scripts.set(name, append(commands, command))
+	return true
+}
+
+func applyPresetComposerScripts(projectPath string) error {
+	docroot := detectDocroot(projectPath)
+	var scripts []composerScript
+	if !opts.quick {
+		scripts = qualityScripts(docroot)
+	}
+	var excludes []string
+	for _, p := range activePresets {
+		scripts = append(scripts, p.composerScripts...)
+		excludes = append(excludes, p.scaffoldExcludes...)
+	}
+	if len(scripts) == 0 && len(excludes) == 0 {
+		return nil
+	}
+
+	composerPath := filepath.Join(projectPath, "composer.json")
+	composer, err := readJSONFile(composerPath)
+	if err != nil {
+		printError("Failed to read composer.json")
+		return err
+	}
+	added := 0
+	for _, s := range scripts {
+		if appendComposerScript(composer.object("scripts"), s.name, s.command) {
+			added++
+		}
+	}
+	if len(excludes) > 0 {
+		mapping := composer.object("extra").object("drupal-scaffold").object("file-mapping")
+		for _, file := range excludes {
+			mapping.set("[web-root]/"+file, false)
+		}
+	}
+	if err := writeJSONFile(composerPath, composer); err != nil {
+		printError("Failed to write composer.json")
+		return err
+	}
+
+	for _, file := range excludes {
+		if err := removeFile(filepath.Join(projectPath, docroot, filepath.FromSlash(file))); err != nil {
+			printWarning(fmt.Sprintf("Could not remove %s/%s: %v", docroot, file, err))
+		}
+	}
+	if len(excludes) > 0 {
+		printStatus(fmt.Sprintf("drupal-scaffold no longer writes %s", strings.Join(excludes, ", ")))
+	}
+	printSuccess(fmt.Sprintf("✓ Added %d composer scripts", added))
+	return nil
+}
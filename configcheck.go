@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type configFile struct {
+	UUID         string `yaml:"uuid"`
+	Dependencies struct {
+		Module   []string `yaml:"module"`
+		Config   []string `yaml:"config"`
+		Enforced struct {
+			Module []string `yaml:"module"`
+		} `yaml:"enforced"`
+	} `yaml:"dependencies"`
+	Module map[string]any `yaml:"module"`
+}
+
+func configStates(projectPath, alias string) (map[string]string, error) {
+	output, err := ddevOutput(projectPath, drushTarget(alias, "config:status", "--state=Any", "--format=json")...)
+	if err != nil {
+		return nil, err
+	}
+	states := map[string]string{}
+	trimmed := strings.TrimSpace(string(output))
+	if trimmed == "" || trimmed == "[]" {
+		return states, nil
+	}
+	var byName map[string]struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+	}
+	if err := json.Unmarshal(output, &byName); err == nil {
+		for name, entry := range byName {
+			states[firstNonEmpty(entry.Name, name)] = entry.State
+		}
+		return states, nil
+	}
+	var list []struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+	}
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, fmt.Errorf("could not parse drush config:status output: %v", err)
+	}
+	for _, entry := range list {
+		states[entry.Name] = entry.State
+	}
+	return states, nil
+}
+
+func readSyncConfig(syncPath string) (map[string]configFile, error) {
+	matches, err := filepath.Glob(filepath.Join(syncPath, "*.yml"))
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]configFile{}
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var file configFile
+		if err := yamlUnmarshal(data, &file); err != nil {
+			printWarning(fmt.Sprintf("Could not read %s: %v", filepath.Base(path), err))
+			continue
+		}
+		files[strings.TrimSuffix(filepath.Base(path), ".yml")] = file
+	}
+	return files, nil
+}
+
+func configImportProblems(projectPath, alias string, sync map[string]configFile, states map[string]string, partial bool) ([]string, error) {
+	enabled, err := enabledModulesAt(projectPath, alias)
+	if err != nil {
+		return nil, fmt.Errorf("could not list enabled modules: %v", err)
+	}
+	available := map[string]bool{"core": true}
+	if extension, ok := sync["core.extension"]; ok && !partial {
+		for module := range extension.Module {
+			available[module] = true
+		}
+	} else {
+		for module := range enabled {
+			available[module] = true
+		}
+	}
+
+	missingModules := map[string][]string{}
+	missingConfig := map[string][]string{}
+	for name, file := range sync {
+		for _, module := range append(file.Dependencies.Module, file.Dependencies.Enforced.Module...) {
+			if !available[module] {
+				missingModules[module] = append(missingModules[module], name)
+			}
+		}
+		for _, dep := range file.Dependencies.Config {
+			_, inSync := sync[dep]
+			state, known := states[dep]
+			if !inSync && (!known || state == "Only in sync dir") {
+				missingConfig[dep] = append(missingConfig[dep], name)
+			}
+		}
+	}
+
+	var problems []string
+	for _, module := range sortedKeys(missingModules) {
+		problems = append(problems, fmt.Sprintf("module %s is not enabled but %s depend on it", module, describeConfigList(missingModules[module])))
+	}
+	for _, dep := range sortedKeys(missingConfig) {
+		problems = append(problems, fmt.Sprintf("config %s does not exist but %s depend on it", dep, describeConfigList(missingConfig[dep])))
+	}
+
+	if site, ok := sync["system.site"]; ok && site.UUID != "" {
+		output, err := ddevOutput(projectPath, drushTarget(alias, "config:get", "system.site", "uuid", "--format=string")...)
+		if err == nil {
+			if active := strings.TrimSpace(string(output)); active != "" && active != site.UUID {
+				problems = append(problems, fmt.Sprintf("site UUID mismatch: config/sync has %s, the site has %s (the export comes from a different site; config-check --fix-uuid adopts it)", site.UUID, active))
+			}
+		}
+	}
+	return problems, nil
+}
+
+func describeConfigList(names []string) string {
+	sort.Strings(names)
+	if len(names) > 3 {
+		return fmt.Sprintf("%s and %d more", strings.Join(names[:3], ", "), len(names)-3)
+	}
+	return strings.Join(names, ", ")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func printConfigImportPreview(states map[string]string, partial bool) int {
+	labels := []struct{ state, action string }{
+		{"Only in sync dir", "create"},
+		{"Different", "update"},
+		{"Only in DB", "delete"},
+	}
+	changes := 0
+	for _, label := range labels {
+		if partial && label.action == "delete" {
+			continue
+		}
+		var names []string
+		for name, state := range states {
+			if state == label.state {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("  %-6s %s\n", label.action, name)
+		}
+		changes += len(names)
+	}
+	if changes == 0 {
+		printSuccess("✓ Active configuration already matches config/sync")
+	}
+	return changes
+}
+
+func validateConfigImport(projectPath, alias string, partial bool) (int, error) {
+	printStatus(fmt.Sprintf("Checking configuration before import on %s...", contentSiteLabel(alias)))
+	syncPath := filepath.Join(projectPath, "config", "sync")
+	sync, err := readSyncConfig(syncPath)
+	if err != nil {
+		return 0, err
+	}
+	states, err := configStates(projectPath, alias)
+	if err != nil {
+		return 0, fmt.Errorf("drush config:status failed: %v", err)
+	}
+	changes := printConfigImportPreview(states, partial)
+	problems, err := configImportProblems(projectPath, alias, sync, states, partial)
+	if err != nil {
+		return changes, err
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			printError("✗ " + problem)
+		}
+		return changes, fmt.Errorf("config import would fail (%d problems)", len(problems))
+	}
+	printSuccess("✓ Configuration dependencies are satisfied")
+	return changes, nil
+}
+
+func runConfigCheck(args []string) int {
+	fs := flag.NewFlagSet("config-check", flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the project")
+	partial := fs.Bool("partial", false, "Check a partial import (nothing is deleted, modules must already be enabled)")
+	env := fs.String("env", "", "Check against an environment or drush alias instead of the local site ('selected' for the one chosen with 'env use')")
+	fixUUID := fs.Bool("fix-uuid", false, "Adopt the site UUID from config/sync and delete shortcut entities that block the import")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	alias, err := envFlagAlias(projectPath, *env)
+	if err != nil {
+		printError(err.Error())
+		return 2
+	}
+	if *fixUUID {
+		if alias != "" && alias != "@self" {
+			printError("--fix-uuid only changes the local site")
+			return 2
+		}
+		if err := reconcileSiteUUID(projectPath); err != nil {
+			printError(err.Error())
+			return 1
+		}
+	}
+	if _, err := validateConfigImport(projectPath, alias, *partial); err != nil {
+		printError(err.Error())
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var baseConfigIgnore = []string{
+	"environment_indicator.indicator",
+	"devel.settings",
+	"devel.toolbar.settings",
+	"system.menu.devel",
+	"webprofiler.config",
+}
+
+func configIgnoreRules() []string {
+	rules := append([]string(nil), baseConfigIgnore...)
+	for _, p := range activePresets {
+		for _, rule := range p.ignoreConfig {
+			if !containsString(rules, rule) {
+				rules = append(rules, rule)
+			}
+		}
+	}
+	return rules
+}
+
+func configureConfigIgnore(projectPath string) error {
+	printStatus("Configuring config_ignore...")
+
+	rules := configIgnoreRules()
+	list := "['" + strings.Join(rules, "', '") + "']"
+	if err := runDDEVQuiet(projectPath, "drush", "config:set", "config_ignore.settings", "mode", "simple", "--yes"); err != nil {
+		printError("Failed to set config_ignore mode")
+		return err
+	}
+	if err := runDDEVQuiet(projectPath, "drush", "config:set", "config_ignore.settings", "ignored_config_entities", list, "--input-format=yaml", "--yes"); err != nil {
+		printError("Failed to set config_ignore rules")
+		return err
+	}
+	if err := exportConfig(projectPath, "config_ignore.settings"); err != nil {
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("✓ config_ignore configured with %d rules", len(rules)))
+	return nil
+}
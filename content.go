@@ -0,0 +1,213 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const contentSyncModule = "single_content_sync"
+
+const containerProjectRoot = "/var/www/html"
+
+func parseContentEntities(args []string) (map[string][]string, error) {
+	entities := map[string][]string{}
+	for _, arg := range args {
+		for _, item := range strings.Split(arg, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			entityType, id, ok := strings.Cut(item, ":")
+			if !ok || entityType == "" || id == "" || strings.Trim(id, "0123456789") != "" {
+				return nil, fmt.Errorf("invalid entity %q (expected type:id, e.g. node:12)", item)
+			}
+			entities[entityType] = append(entities[entityType], id)
+		}
+	}
+	if len(entities) == 0 {
+		return nil, fmt.Errorf("no entities given")
+	}
+	return entities, nil
+}
+
+func sortedEntityTypes(entities map[string][]string) []string {
+	types := make([]string, 0, len(entities))
+	for t := range entities {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
+
+func drushTarget(alias string, args ...string) []string {
+	if alias == "" || alias == "@self" {
+		return append([]string{"drush"}, args...)
+	}
+	return append([]string{"drush", alias}, args...)
+}
+
+func requireContentSync(projectPath, alias string) error {
+	output, err := ddevOutput(projectPath, drushTarget(alias, "pm:list", "--status=enabled", "--field=name")...)
+	if err != nil {
+		return fmt.Errorf("could not list modules on %s: %v", contentSiteLabel(alias), err)
+	}
+	for _, name := range strings.Fields(string(output)) {
+		if name == contentSyncModule {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not enabled on %s; run 'ddev composer require drupal/%s' and enable it everywhere content is moved", contentSyncModule, contentSiteLabel(alias), contentSyncModule)
+}
+
+func contentSiteLabel(alias string) string {
+	if alias == "" || alias == "@self" {
+		return "the local site"
+	}
+	return alias
+}
+
+func exportContent(projectPath, alias, dir string, entities map[string][]string) error {
+	for _, entityType := range sortedEntityTypes(entities) {
+		args := drushTarget(alias, "content:export", entityType, dir,
+			"--entities="+strings.Join(entities[entityType], ","), "--assets", "--translate")
+		if err := runDDEV(projectPath, args...); err != nil {
+			return fmt.Errorf("exporting %s entities: %v", entityType, err)
+		}
+	}
+	return nil
+}
+
+func importContent(projectPath, alias string, files []string) error {
+	for _, file := range files {
+		if err := runDDEV(projectPath, drushTarget(alias, "content:import", file)...); err != nil {
+			return fmt.Errorf("importing %s: %v", filepath.Base(file), err)
+		}
+	}
+	return nil
+}
+
+func runContent(args []string) int {
+	if len(args) > 0 && args[0] == "generate" {
+		return runContentGenerate(args[1:])
+	}
+	if len(args) == 0 || (args[0] != "pull" && args[0] != "push") {
+		printError("Usage: install-drupal content generate | install-drupal content pull [--from <env|@alias>] <type:id>... | install-drupal content push [--to <env|@alias>] <type:id>...")
+		return 2
+	}
+	direction := args[0]
+	fs := flag.NewFlagSet("content "+direction, flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the local project")
+	from := fs.String("from", "", "Environment or drush site alias to pull content from (default: the selected environment)")
+	to := fs.String("to", "", "Environment or drush site alias to push content to (default: the selected environment)")
+	yes := fs.Bool("yes", false, "Push without asking for confirmation")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	remote, remoteFlag := *from, "from"
+	if direction == "push" {
+		remote, remoteFlag = *to, "to"
+	}
+	entities, err := parseContentEntities(fs.Args())
+	if err != nil {
+		printError(err.Error())
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if remote, err = resolveEnvironment(projectPath, remote); err != nil {
+		printError(fmt.Sprintf("content %s: %v (--%s)", direction, err, remoteFlag))
+		return 2
+	}
+	if remote == "@self" {
+		printError(fmt.Sprintf("content %s needs a remote environment, not the local site", direction))
+		return 2
+	}
+	opts.interactive = stdinIsTerminal()
+
+	for _, alias := range []string{"", remote} {
+		if err := requireContentSync(projectPath, alias); err != nil {
+			printError(err.Error())
+			return 1
+		}
+	}
+
+	stamp := time.Now().Format("20060102-150405")
+	localDir := filepath.Join(projectPath, ".content-sync", stamp)
+	containerDir := containerProjectRoot + "/.content-sync/" + stamp
+	remoteDir := "/tmp/drupal-scripts-content-" + stamp
+	if err := ensureDir(localDir); err != nil {
+		printError(err.Error())
+		return 1
+	}
+	defer os.RemoveAll(filepath.Join(projectPath, ".content-sync"))
+
+	count := 0
+	for _, ids := range entities {
+		count += len(ids)
+	}
+
+	if direction == "pull" {
+		printStatus(fmt.Sprintf("Pulling %d entities from %s...", count, remote))
+		if err := exportContent(projectPath, remote, remoteDir, entities); err != nil {
+			printError(err.Error())
+			return 1
+		}
+		if err := runDDEV(projectPath, "drush", "core:rsync", "--yes", remote+":"+remoteDir+"/", containerDir+"/"); err != nil {
+			printError(fmt.Sprintf("Copying the export from %s failed: %v", remote, err))
+			return 1
+		}
+		zips, _ := filepath.Glob(filepath.Join(localDir, "*.zip"))
+		if len(zips) == 0 {
+			printError("The export produced no archives")
+			return 1
+		}
+		var files []string
+		for _, zip := range zips {
+			files = append(files, containerDir+"/"+filepath.Base(zip))
+		}
+		if err := importContent(projectPath, "", files); err != nil {
+			printError(err.Error())
+			return 1
+		}
+		runDDEVQuiet(projectPath, drushTarget(remote, "site:ssh", "rm -rf "+remoteDir)...)
+		printSuccess(fmt.Sprintf("✓ Pulled %d entities from %s", count, remote))
+		return 0
+	}
+
+	if !confirm(fmt.Sprintf("Create or overwrite %d entities on %s?", count, remote), *yes) {
+		return 1
+	}
+	printStatus(fmt.Sprintf("Pushing %d entities to %s...", count, remote))
+	if err := exportContent(projectPath, "", containerDir, entities); err != nil {
+		printError(err.Error())
+		return 1
+	}
+	zips, _ := filepath.Glob(filepath.Join(localDir, "*.zip"))
+	if len(zips) == 0 {
+		printError("The export produced no archives")
+		return 1
+	}
+	if err := runDDEV(projectPath, "drush", "core:rsync", "--yes", containerDir+"/", remote+":"+remoteDir+"/"); err != nil {
+		printError(fmt.Sprintf("Copying the export to %s failed: %v", remote, err))
+		return 1
+	}
+	var files []string
+	for _, zip := range zips {
+		files = append(files, remoteDir+"/"+filepath.Base(zip))
+	}
+	if err := importContent(projectPath, remote, files); err != nil {
+		printError(err.Error())
+		return 1
+	}
+	runDDEVQuiet(projectPath, drushTarget(remote, "site:ssh", "rm -rf "+remoteDir)...)
+	printSuccess(fmt.Sprintf("✓ Pushed %d entities to %s", count, remote))
+	return 0
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type landoFile struct {
+	Name     string                    `yaml:"name"`
+	Recipe   string                    `yaml:"recipe"`
+	Config   landoConfig               `yaml:"config"`
+	Proxy    map[string][]any          `yaml:"proxy"`
+	Services map[string]map[string]any `yaml:"services"`
+}
+
+type landoConfig struct {
+	PHP      string `yaml:"php"`
+	Webroot  string `yaml:"webroot"`
+	Database string `yaml:"database"`
+	Via      string `yaml:"via"`
+}
+
+type convertedProject struct {
+	source      string
+	name        string
+	projectType string
+	docroot     string
+	php         string
+	database    string
+	webserver   string
+	hostnames   []string
+	fqdns       []string
+	addons      []string
+	warnings    []string
+
+	disableSettingsMgmt bool
+}
+
+var ddevAddonsForService = map[string]string{
+	"solr":          "ddev/ddev-solr",
+	"redis":         "ddev/ddev-redis",
+	"memcached":     "ddev/ddev-memcached",
+	"elasticsearch": "ddev/ddev-elasticsearch",
+	"varnish":       "ddev/ddev-varnish",
+}
+
+var ddevBuiltinServices = map[string]string{
+	"mailhog":    "Mailpit is built into DDEV",
+	"mailpit":    "Mailpit is built into DDEV",
+	"phpmyadmin": "use 'ddev add-on get ddev/ddev-phpmyadmin'",
+	"adminer":    "use 'ddev add-on get ddev/ddev-adminer'",
+}
+
+var phpVersionPattern = regexp.MustCompile(`php-?(\d+\.\d+)`)
+
+func landoProjectType(recipe string) string {
+	switch recipe {
+	case "drupal7", "drupal8", "drupal9", "drupal10", "drupal11", "backdrop", "wordpress", "laravel":
+		return recipe
+	case "pantheon", "acquia", "platformsh":
+		return ""
+	}
+	return "php"
+}
+
+func ddevWebserver(via string) string {
+	switch strings.SplitN(via, ":", 2)[0] {
+	case "nginx":
+		return "nginx-fpm"
+	case "apache":
+		return "apache-fpm"
+	}
+	return ""
+}
+
+func ddevDatabase(image string) string {
+	for _, db := range []string{"mariadb", "mysql", "postgres"} {
+		if i := strings.Index(image, db+":"); i >= 0 {
+			version := strings.SplitN(image[i+len(db)+1:], "-", 2)[0]
+			return db + ":" + version
+		}
+	}
+	return ""
+}
+
+func (c *convertedProject) addHostname(host, localDomain string) {
+	host = strings.SplitN(strings.TrimPrefix(strings.TrimPrefix(host, "http://"), "https://"), ":", 2)[0]
+	if host == "" {
+		return
+	}
+	if strings.HasSuffix(host, "."+localDomain) {
+		name := strings.TrimSuffix(host, "."+localDomain)
+		if name != c.name && !containsString(c.hostnames, name) {
+			c.hostnames = append(c.hostnames, name)
+		}
+		return
+	}
+	if !containsString(c.fqdns, host) {
+		c.fqdns = append(c.fqdns, host)
+	}
+}
+
+func (c *convertedProject) addService(name, serviceType string) {
+	base := strings.SplitN(serviceType, ":", 2)[0]
+	if base == "" {
+		base = name
+	}
+	switch {
+	case ddevAddonsForService[base] != "":
+		c.addons = append(c.addons, ddevAddonsForService[base])
+	case ddevBuiltinServices[base] != "":
+		c.warnings = append(c.warnings, fmt.Sprintf("service %s: %s", name, ddevBuiltinServices[base]))
+	case base == "php", base == "nginx", base == "apache", base == "mysql", base == "mariadb", base == "postgres", base == "compose":
+	default:
+		c.warnings = append(c.warnings, fmt.Sprintf("service %s (%s) has no DDEV equivalent; add it as a custom docker-compose service", name, serviceType))
+	}
+}
+
+func readLandoProject(projectPath string) (*convertedProject, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, ".lando.yml"))
+	if err != nil {
+		return nil, err
+	}
+	var lando landoFile
+	if err := yamlUnmarshal(data, &lando); err != nil {
+		return nil, fmt.Errorf(".lando.yml: %v", err)
+	}
+
+	c := &convertedProject{
+		source:      ".lando.yml",
+		name:        lando.Name,
+		projectType: landoProjectType(lando.Recipe),
+		docroot:     lando.Config.Webroot,
+		php:         lando.Config.PHP,
+		database:    ddevDatabase(lando.Config.Database),
+		webserver:   ddevWebserver(lando.Config.Via),
+	}
+	if c.docroot == "." {
+		c.docroot = ""
+	}
+	if c.projectType == "" {
+		c.warnings = append(c.warnings, fmt.Sprintf("recipe %s: hosting integration is not converted; DDEV has its own provider integrations ('ddev pull')", lando.Recipe))
+	}
+
+	services := make([]string, 0, len(lando.Services))
+	for name := range lando.Services {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+	for _, name := range services {
+		serviceType, _ := lando.Services[name]["type"].(string)
+		c.addService(name, serviceType)
+	}
+
+	for _, entries := range lando.Proxy {
+		for _, entry := range entries {
+			switch e := entry.(type) {
+			case string:
+				c.addHostname(e, "lndo.site")
+			case map[string]any:
+				if host, ok := e["hostname"].(string); ok {
+					c.addHostname(host, "lndo.site")
+				}
+			}
+		}
+	}
+	return c, nil
+}
+
+func readEnvFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	env := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(strings.ReplaceAll(value, `\$`, "$")); err == nil && strings.HasPrefix(value, `"`) {
+			value = unquoted
+		} else {
+			value = strings.Trim(value, `"'`)
+		}
+		env[strings.TrimSpace(key)] = value
+	}
+	return env, scanner.Err()
+}
+
+func readDocksalProject(projectPath string) (*convertedProject, error) {
+	dir := filepath.Join(projectPath, ".docksal")
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	env, err := readEnvFile(filepath.Join(dir, "docksal.env"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	c := &convertedProject{
+		source:   ".docksal",
+		name:     filepath.Base(projectPath),
+		docroot:  env["DOCROOT"],
+		database: ddevDatabase(env["DB_IMAGE"]),
+	}
+	if c.docroot == "" {
+		c.docroot = "docroot"
+	}
+	if m := phpVersionPattern.FindStringSubmatch(env["CLI_IMAGE"]); m != nil {
+		c.php = m[1]
+	}
+	if stack := env["DOCKSAL_STACK"]; stack == "default" || stack == "" {
+		c.webserver = "apache-fpm"
+	} else {
+		c.warnings = append(c.warnings, fmt.Sprintf("DOCKSAL_STACK=%s: only the web, cli and db services are converted", stack))
+	}
+	if host := env["VIRTUAL_HOST"]; host != "" {
+		c.name = strings.TrimSuffix(host, ".docksal.site")
+		c.name = strings.TrimSuffix(c.name, ".docksal")
+	}
+
+	if data, err := os.ReadFile(filepath.Join(dir, "docksal.yml")); err == nil {
+		var compose struct {
+			Services map[string]map[string]any `yaml:"services"`
+		}
+		if err := yamlUnmarshal(data, &compose); err != nil {
+			c.warnings = append(c.warnings, fmt.Sprintf("docksal.yml could not be read (%v); check it for extra services", err))
+		}
+		names := make([]string, 0, len(compose.Services))
+		for name := range compose.Services {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if name == "web" || name == "cli" || name == "db" {
+				continue
+			}
+			image, _ := compose.Services[name]["image"].(string)
+			c.addService(name, strings.TrimPrefix(filepath.Base(image), "docksal-"))
+		}
+	}
+	return c, nil
+}
+
+func (c *convertedProject) ddevConfigArgs() []string {
+	args := []string{"config"}
+	if c.name != "" {
+		args = append(args, "--project-name="+c.name)
+	}
+	if c.projectType != "" {
+		args = append(args, "--project-type="+c.projectType)
+	}
+	if c.docroot != "" {
+		args = append(args, "--docroot="+c.docroot)
+	}
+	if c.php != "" {
+		args = append(args, "--php-version="+c.php)
+	}
+	if c.database != "" {
+		args = append(args, "--database="+c.database)
+	}
+	if c.webserver != "" {
+		args = append(args, "--webserver-type="+c.webserver)
+	}
+	if len(c.hostnames) > 0 {
+		args = append(args, "--additional-hostnames="+strings.Join(c.hostnames, ","))
+	}
+	if len(c.fqdns) > 0 {
+		args = append(args, "--additional-fqdns="+strings.Join(c.fqdns, ","))
+	}
+	if c.disableSettingsMgmt {
+		args = append(args, "--disable-settings-management")
+	}
+	return args
+}
+
+func runConvert(args []string) int {
+	fs := flag.NewFlagSet("convert", flag.ContinueOnError)
+	path := fs.String("path", ".", "Path to the existing Lando, Docksal or Acquia BLT project")
+	dbDump := fs.String("db", "", "Database dump to import after conversion")
+	addons := fs.Bool("addons", true, "Install DDEV add-ons for services such as Solr and Redis")
+	dryRun := fs.Bool("dry-run", false, "Print the DDEV configuration without writing it")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	var converted *convertedProject
+	if _, err := os.Stat(filepath.Join(projectPath, ".lando.yml")); err == nil {
+		converted, err = readLandoProject(projectPath)
+		if err != nil {
+			printError(err.Error())
+			return 1
+		}
+	} else if _, err := os.Stat(filepath.Join(projectPath, ".docksal")); err == nil {
+		converted, err = readDocksalProject(projectPath)
+		if err != nil {
+			printError(err.Error())
+			return 1
+		}
+	} else if isBLTProject(projectPath) {
+		converted, err = readBLTProject(projectPath)
+		if err != nil {
+			printError(err.Error())
+			return 1
+		}
+	} else {
+		printError(fmt.Sprintf("No .lando.yml, .docksal or blt/blt.yml found in %s", projectPath))
+		return 1
+	}
+
+	if isBLTProject(projectPath) {
+		converted.disableSettingsMgmt = true
+	}
+	configArgs := converted.ddevConfigArgs()
+	printStatus(fmt.Sprintf("Converting %s: ddev %s", converted.source, strings.Join(configArgs, " ")))
+	for _, addon := range converted.addons {
+		printStatus(fmt.Sprintf("Add-on: ddev add-on get %s", addon))
+	}
+	for _, warning := range converted.warnings {
+		printWarning(warning)
+	}
+	if *dryRun {
+		return 0
+	}
+
+	if _, err := os.Stat(filepath.Join(projectPath, ".ddev", "config.yaml")); err == nil {
+		printError(".ddev/config.yaml already exists; remove it to convert again")
+		return 1
+	}
+	if !commandExists("ddev") {
+		printError("DDEV is not installed")
+		return 1
+	}
+
+	if err := runDDEV(projectPath, configArgs...); err != nil {
+		printError("ddev config failed")
+		return 1
+	}
+	if isBLTProject(projectPath) {
+		if err := setUpBLTProject(projectPath, projectDocroot(projectPath)); err != nil {
+			printError(fmt.Sprintf("Failed to set up BLT compatibility: %v", err))
+			return 1
+		}
+	}
+	if *addons {
+		for _, addon := range converted.addons {
+			if err := runDDEV(projectPath, "add-on", "get", addon); err != nil {
+				printWarning(fmt.Sprintf("Failed to install add-on %s", addon))
+			}
+		}
+	}
+	if err := runDDEV(projectPath, "start"); err != nil {
+		printError("ddev start failed")
+		return 1
+	}
+	if *dbDump != "" {
+		dump, err := filepath.Abs(*dbDump)
+		if err != nil {
+			printError(err.Error())
+			return 1
+		}
+		if err := runDDEV(projectPath, "import-db", "--file="+dump); err != nil {
+			printError("Database import failed")
+			return 1
+		}
+	}
+
+	printStatus("Checking that the site boots...")
+	if err := checkBootstrap(projectPath); err != nil {
+		printError(fmt.Sprintf("✗ %v", err))
+		if *dbDump == "" {
+			fmt.Println("Import a database with --db or 'ddev import-db', then run 'install-drupal verify'.")
+		}
+		return 1
+	}
+	siteURL := getSiteURL(projectPath)
+	if !projectHealthy(siteURL) {
+		printError(fmt.Sprintf("✗ %s is not responding", siteURL))
+		return 1
+	}
+	registerProject(projectPath, siteURL, nil)
+
+	printSuccess(fmt.Sprintf("✓ Converted to DDEV; the site boots at %s", siteURL))
+	sendWebhooks("convert", "Converted "+filepath.Base(projectPath)+" to DDEV", fmt.Sprintf("The site boots at %s", siteURL))
+	fmt.Printf("Once everything works, %s can be removed.\n", converted.source)
+	return 0
+}
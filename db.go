@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+)
+
+func findProjectRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, ".ddev", "config.yaml")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("not inside a DDEV project (no .ddev/config.yaml found)")
+		}
+		dir = parent
+	}
+}
+
+func projectUsesPostgres(projectPath string) bool {
+	describe, err := ddevDescribe(projectPath)
+	if err != nil {
+		return false
+	}
+	dbType, _ := describe["database_type"].(string)
+	return dbType == "postgres"
+}
+
+func dbQueryRows(projectPath, query string) ([][]string, error) {
+	args := []string{"mysql", "--batch", "-e", query}
+	if projectUsesPostgres(projectPath) {
+		args = []string{"psql", "--no-align", "--field-separator=\t", "--pset=footer=off", "-c", query}
+	}
+	output, err := ddevOutput(projectPath, args...)
+	if err != nil {
+		return nil, err
+	}
+	var rows [][]string
+	for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		rows = append(rows, strings.Split(line, "\t"))
+	}
+	return rows, nil
+}
+
+func printQueryRows(rows [][]string, format string) error {
+	if len(rows) == 0 {
+		if format == "json" {
+			fmt.Println("[]")
+		}
+		return nil
+	}
+	switch format {
+	case "csv":
+		w := csv.NewWriter(os.Stdout)
+		w.WriteAll(rows)
+		return w.Error()
+	case "json":
+		records := make([]map[string]string, 0, len(rows)-1)
+		for _, row := range rows[1:] {
+			record := map[string]string{}
+			for i, column := range rows[0] {
+				if i < len(row) {
+					record[column] = row[i]
+				}
+			}
+			records = append(records, record)
+		}
+		data, err := json.MarshalIndent(records, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	case "tsv":
+		for _, row := range rows {
+			fmt.Println(strings.Join(row, "\t"))
+		}
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for i, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+		if i == 0 {
+			separators := make([]string, len(row))
+			for j, column := range row {
+				separators[j] = strings.Repeat("-", len(column))
+			}
+			fmt.Fprintln(w, strings.Join(separators, "\t"))
+		}
+	}
+	w.Flush()
+	fmt.Printf("(%d rows)\n", len(rows)-1)
+	return nil
+}
+
+func runDB(args []string) int {
+	if len(args) == 0 || (args[0] != "shell" && args[0] != "query") {
+		printError("Usage: install-drupal db shell | install-drupal db query [--format table|csv|json|tsv] <sql>")
+		return 2
+	}
+	fs := flag.NewFlagSet("db "+args[0], flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the project")
+	format := fs.String("format", "table", "Query output format: table, csv, json or tsv")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	if args[0] == "shell" {
+		client := "mysql"
+		if projectUsesPostgres(projectPath) {
+			client = "psql"
+		}
+		cmd := exec.Command("ddev", append([]string{client}, fs.Args()...)...)
+		cmd.Dir = projectPath
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				return exitErr.ExitCode()
+			}
+			printError(err.Error())
+			return 1
+		}
+		return 0
+	}
+
+	switch *format {
+	case "table", "csv", "json", "tsv":
+	default:
+		printError(fmt.Sprintf("Unknown format %q (expected table, csv, json or tsv)", *format))
+		return 2
+	}
+	query := strings.Join(fs.Args(), " ")
+	if query == "-" {
+		data, err := io.ReadAll(stdinReader)
+		if err != nil {
+			printError(err.Error())
+			return 1
+		}
+		query = string(data)
+	}
+	if strings.TrimSpace(query) == "" {
+		printError("Usage: install-drupal db query [--format table|csv|json|tsv] <sql>  (use - to read SQL from stdin)")
+		return 2
+	}
+	rows, err := dbQueryRows(projectPath, query)
+	if err != nil {
+		printError(fmt.Sprintf("Query failed: %v", err))
+		return 1
+	}
+	if err := printQueryRows(rows, *format); err != nil {
+		printError(err.Error())
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+var databaseUIAddons = map[string]struct {
+	addon string
+	port  string
+}{
+	"phpmyadmin": {"ddev/ddev-phpmyadmin", "8037"},
+	"adminer":    {"ddev/ddev-adminer", "9101"},
+}
+
+func databaseUITool() (string, error) {
+	tool := opts.dbUI
+	if tool == "" {
+		tool = project.DDEV.DBUI
+	}
+	tool = strings.ToLower(tool)
+	if tool == "" || tool == "none" {
+		return "", nil
+	}
+	if _, ok := databaseUIAddons[tool]; !ok {
+		return "", fmt.Errorf("invalid database UI %q (expected phpmyadmin, adminer or none)", tool)
+	}
+	return tool, nil
+}
+
+func installDatabaseUI(projectPath string) error {
+	tool, _ := databaseUITool()
+	if tool == "" {
+		return nil
+	}
+	printStatus(fmt.Sprintf("Installing %s...", tool))
+	if err := runDDEV(projectPath, "add-on", "get", databaseUIAddons[tool].addon); err != nil {
+		printError(fmt.Sprintf("Failed to install the %s add-on", tool))
+		return err
+	}
+	printSuccess(fmt.Sprintf("✓ %s add-on installed", tool))
+	return nil
+}
+
+func databaseUIURL(projectPath, siteURL, tool string) string {
+	if describe, err := ddevDescribe(projectPath); err == nil {
+		if services, ok := describe["services"].(map[string]any); ok {
+			if service, ok := services[tool].(map[string]any); ok {
+				if u, ok := service["https_url"].(string); ok && u != "" {
+					return u
+				}
+			}
+		}
+	}
+	if u, err := url.Parse(siteURL); err == nil && u.Hostname() != "" {
+		return "https://" + u.Hostname() + ":" + databaseUIAddons[tool].port
+	}
+	return ""
+}
@@ -0,0 +1,351 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const minDDEVVersion = "1.24.0"
+
+//go:embed ddev/commands
+var embeddedDDEVCommands embed.FS
+
+//go:embed ddev/*.tmpl
+var embeddedDDEVTemplates embed.FS
+
+type ddevSettings struct {
+	AdditionalHostnames   []string          `yaml:"additional_hostnames"`
+	AdditionalFQDNs       []string          `yaml:"additional_fqdns"`
+	WebEnvironment        []string          `yaml:"web_environment"`
+	WebimageExtraPackages []string          `yaml:"webimage_extra_packages"`
+	Files                 map[string]string `yaml:"files"`
+	DBUI                  string            `yaml:"db_ui"`
+	Cron                  string            `yaml:"cron"`
+	PHPVersion            string            `yaml:"php_version"`
+}
+
+type phpSettings struct {
+	MemoryLimit       string            `yaml:"memory_limit"`
+	MaxExecutionTime  string            `yaml:"max_execution_time"`
+	UploadMaxFilesize string            `yaml:"upload_max_filesize"`
+	PostMaxSize       string            `yaml:"post_max_size"`
+	Opcache           map[string]string `yaml:"opcache"`
+	Ini               map[string]string `yaml:"ini"`
+}
+
+func (p phpSettings) empty() bool {
+	return p.MemoryLimit == "" && p.MaxExecutionTime == "" && p.UploadMaxFilesize == "" &&
+		p.PostMaxSize == "" && len(p.Opcache) == 0 && len(p.Ini) == 0
+}
+
+type webserverRedirect struct {
+	From   string `yaml:"from"`
+	To     string `yaml:"to"`
+	Status int    `yaml:"status"`
+}
+
+func (r webserverRedirect) StatusCode() int {
+	if r.Status == 0 {
+		return 301
+	}
+	return r.Status
+}
+
+type webserverSettings struct {
+	AdminAllowIPs []string            `yaml:"admin_allow_ips"`
+	Redirects     []webserverRedirect `yaml:"redirects"`
+	Nginx         map[string]string   `yaml:"nginx"`
+	Apache        map[string]string   `yaml:"apache"`
+}
+
+type ddevTemplateData struct {
+	ddevSettings
+	ProjectName string
+	ProjectPath string
+	Docroot     string
+	CronSeconds int
+}
+
+func installDDEVCommands(projectPath string) error {
+	printStatus("Installing DDEV custom commands...")
+
+	installed := 0
+	err := fs.WalkDir(embeddedDDEVCommands, "ddev/commands", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel("ddev", filepath.FromSlash(path))
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(projectPath, ".ddev", rel)
+		if _, err := os.Stat(target); err == nil {
+			printStatus(fmt.Sprintf("Keeping existing .ddev/%s", filepath.ToSlash(rel)))
+			return nil
+		}
+		data, err := embeddedDDEVCommands.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := writeExecutable(target, data); err != nil {
+			return err
+		}
+		installed++
+		return nil
+	})
+	if err != nil {
+		printError(fmt.Sprintf("Failed to install DDEV commands: %v", err))
+		return err
+	}
+
+	printSuccess(fmt.Sprintf("✓ Installed %d DDEV custom commands (ddev phpcs, phpcbf, phpunit, uli, cex, cim)", installed))
+	return nil
+}
+
+func renderDDEVTemplate(name, body string, data any) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(configTemplateFuncs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func validDDEVOverrideName(name string) bool {
+	if filepath.Base(name) != name || !strings.HasSuffix(name, ".yaml") {
+		return false
+	}
+	return strings.HasPrefix(name, "config.") || strings.HasPrefix(name, "docker-compose.")
+}
+
+func cronInterval() (time.Duration, error) {
+	value := opts.cron
+	if value == "" {
+		value = project.DDEV.Cron
+	}
+	switch strings.ToLower(value) {
+	case "":
+		return 10 * time.Minute, nil
+	case "off", "0", "false", "no":
+		return 0, nil
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil || interval < time.Minute {
+		return 0, fmt.Errorf("invalid cron interval %q (expected a duration of at least 1m, such as 15m or 1h, or off)", value)
+	}
+	return interval, nil
+}
+
+func writeDDEVOverrides(projectPath string, settings ddevSettings) error {
+	interval, _ := cronInterval()
+	if len(settings.AdditionalHostnames) == 0 && len(settings.AdditionalFQDNs) == 0 &&
+		len(settings.WebEnvironment) == 0 && len(settings.WebimageExtraPackages) == 0 && len(settings.Files) == 0 && interval == 0 {
+		return nil
+	}
+	printStatus("Writing DDEV configuration overrides...")
+
+	data := ddevTemplateData{
+		ddevSettings: settings,
+		ProjectName:  filepath.Base(projectPath),
+		ProjectPath:  projectPath,
+		Docroot:      projectDocroot(projectPath),
+		CronSeconds:  int(interval.Seconds()),
+	}
+	ddevDir := filepath.Join(projectPath, ".ddev")
+
+	if len(settings.AdditionalHostnames) > 0 || len(settings.AdditionalFQDNs) > 0 || len(settings.WebEnvironment) > 0 ||
+		len(settings.WebimageExtraPackages) > 0 || interval > 0 {
+		body, err := embeddedDDEVTemplates.ReadFile("ddev/config.drupal-scripts.yaml.tmpl")
+		if err != nil {
+			return err
+		}
+		content, err := renderDDEVTemplate("config.drupal-scripts.yaml", string(body), data)
+		if err != nil {
+			printError(fmt.Sprintf("Failed to render DDEV config: %v", err))
+			return err
+		}
+		if err := writeFile(filepath.Join(ddevDir, "config.drupal-scripts.yaml"), content); err != nil {
+			printError("Failed to write .ddev/config.drupal-scripts.yaml")
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(settings.Files))
+	for name := range settings.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !validDDEVOverrideName(name) {
+			printError(fmt.Sprintf("Invalid DDEV override file name %q (expected config.*.yaml or docker-compose.*.yaml)", name))
+			return fmt.Errorf("invalid ddev override %s", name)
+		}
+		content, err := renderDDEVTemplate(name, settings.Files[name], data)
+		if err != nil {
+			printError(fmt.Sprintf("Failed to render .ddev/%s: %v", name, err))
+			return err
+		}
+		if err := writeFile(filepath.Join(ddevDir, name), content); err != nil {
+			printError(fmt.Sprintf("Failed to write .ddev/%s", name))
+			return err
+		}
+		printStatus(fmt.Sprintf("Wrote .ddev/%s", name))
+	}
+
+	printSuccess("✓ DDEV configuration overrides written")
+	return nil
+}
+
+func writePHPIni(projectPath string, settings phpSettings) error {
+	if settings.empty() {
+		return nil
+	}
+	printStatus("Writing PHP ini overrides...")
+
+	if settings.PostMaxSize == "" {
+		settings.PostMaxSize = settings.UploadMaxFilesize
+	}
+
+	body, err := embeddedDDEVTemplates.ReadFile("ddev/php.ini.tmpl")
+	if err != nil {
+		return err
+	}
+	content, err := renderDDEVTemplate("php.ini", string(body), settings)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to render PHP ini overrides: %v", err))
+		return err
+	}
+
+	iniPath := filepath.Join(projectPath, ".ddev", "php", "drupal-scripts.ini")
+	if err := writeFile(iniPath, content); err != nil {
+		printError("Failed to write .ddev/php/drupal-scripts.ini")
+		return err
+	}
+
+	printSuccess("✓ PHP ini overrides written to .ddev/php/drupal-scripts.ini")
+	return nil
+}
+
+func writeDDEVSnippets(projectPath, dir string, snippets map[string]string, data any) error {
+	names := make([]string, 0, len(snippets))
+	for name := range snippets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if filepath.Base(name) != name || !strings.HasSuffix(name, ".conf") {
+			printError(fmt.Sprintf("Invalid %s snippet name %q (expected <name>.conf)", dir, name))
+			return fmt.Errorf("invalid snippet %s", name)
+		}
+		content, err := renderDDEVTemplate(name, snippets[name], data)
+		if err != nil {
+			printError(fmt.Sprintf("Failed to render .ddev/%s/%s: %v", dir, name, err))
+			return err
+		}
+		if err := writeFile(filepath.Join(projectPath, ".ddev", dir, name), content); err != nil {
+			printError(fmt.Sprintf("Failed to write .ddev/%s/%s", dir, name))
+			return err
+		}
+		printStatus(fmt.Sprintf("Wrote .ddev/%s/%s", dir, name))
+	}
+	return nil
+}
+
+func writeWebserverConfig(projectPath string, settings webserverSettings) error {
+	if len(settings.AdminAllowIPs) == 0 && len(settings.Redirects) == 0 &&
+		len(settings.Nginx) == 0 && len(settings.Apache) == 0 {
+		return nil
+	}
+	printStatus("Writing webserver configuration snippets...")
+
+	for _, r := range settings.Redirects {
+		if !strings.HasPrefix(r.From, "/") || r.To == "" {
+			printError(fmt.Sprintf("Invalid redirect %q -> %q (from must start with /)", r.From, r.To))
+			return fmt.Errorf("invalid redirect")
+		}
+	}
+
+	if len(settings.AdminAllowIPs) > 0 || len(settings.Redirects) > 0 {
+		for _, server := range []string{"nginx", "apache"} {
+			body, err := embeddedDDEVTemplates.ReadFile("ddev/" + server + ".conf.tmpl")
+			if err != nil {
+				return err
+			}
+			content, err := renderDDEVTemplate(server+".conf", string(body), settings)
+			if err != nil {
+				printError(fmt.Sprintf("Failed to render %s snippet: %v", server, err))
+				return err
+			}
+			if err := writeFile(filepath.Join(projectPath, ".ddev", server, "drupal-scripts.conf"), content); err != nil {
+				printError(fmt.Sprintf("Failed to write .ddev/%s/drupal-scripts.conf", server))
+				return err
+			}
+		}
+	}
+
+	data := ddevTemplateData{ProjectName: filepath.Base(projectPath), ProjectPath: projectPath, Docroot: projectDocroot(projectPath)}
+	if err := writeDDEVSnippets(projectPath, "nginx", settings.Nginx, data); err != nil {
+		return err
+	}
+	if err := writeDDEVSnippets(projectPath, "apache", settings.Apache, data); err != nil {
+		return err
+	}
+
+	printSuccess("✓ Webserver configuration snippets written")
+	return nil
+}
+
+func installedDDEVVersion() string {
+	output, err := exec.Command("ddev", "--version").Output()
+	if err != nil {
+		return ""
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(fields[len(fields)-1], "v")
+}
+
+func upgradeDDEV() error {
+	printStatus("Upgrading DDEV via Homebrew...")
+	if err := brewInstallVerified("upgrade", "ddev/ddev/ddev"); err != nil {
+		printError("Failed to upgrade DDEV")
+		return err
+	}
+	printSuccess(fmt.Sprintf("✓ DDEV upgraded to %s", installedDDEVVersion()))
+	migrateManagedProjects()
+	return nil
+}
+
+func migrateManagedProjects() {
+	projects, err := loadRegistry()
+	if err != nil || len(projects) == 0 {
+		return
+	}
+
+	printStatus("Migrating DDEV configuration of managed projects...")
+	for _, p := range projects {
+		if _, err := os.Stat(filepath.Join(p.Path, ".ddev", "config.yaml")); err != nil {
+			continue
+		}
+		if err := runDDEV(p.Path, "config", "--auto"); err != nil {
+			printWarning(fmt.Sprintf("Could not migrate %s; run 'ddev config --auto' in %s", p.Name, p.Path))
+			continue
+		}
+		printSuccess(fmt.Sprintf("✓ %s migrated", p.Name))
+	}
+	printStatus("Restart running projects with 'ddev restart' to apply the new configuration.")
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const dockerStartTimeout = 90 * time.Second
+
+type recoveryAction struct {
+	key       string
+	label     string
+	command   string
+	confirm   string
+	available func() bool
+	run       func(projectPath string) error
+}
+
+type ddevFailure struct {
+	pattern *regexp.Regexp
+	problem string
+	actions []recoveryAction
+}
+
+var ddevFailures = []ddevFailure{
+	{
+		pattern: regexp.MustCompile(`(?i)cannot connect to the docker daemon|docker is not running|could not connect to a docker provider|is the docker daemon running|error during connect`),
+		problem: "Docker is not running",
+		actions: []recoveryAction{
+			{key: "c", label: "Start Colima", command: "colima start", available: func() bool { return commandExists("colima") },
+				run: func(string) error {
+					startColima()
+					if exec.Command("colima", "status").Run() != nil {
+						return fmt.Errorf("colima did not start")
+					}
+					return nil
+				}},
+			{key: "d", label: "Open Docker Desktop and wait for it", command: "open -a Docker", available: dockerDesktopInstalled,
+				run: func(string) error {
+					if err := runCommand("open", "-a", "Docker"); err != nil {
+						return err
+					}
+					return waitForDocker(dockerStartTimeout)
+				}},
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)port is already allocated|address already in use|unable to listen on required ports|ports? (?:\S+ )?(?:is|are) (?:already )?in use|bind: .*in use`),
+		problem: "a port the DDEV router needs is taken by another program or DDEV project",
+		actions: []recoveryAction{
+			{key: "p", label: "Stop all DDEV projects and the router", command: "ddev poweroff",
+				run: func(string) error { return runCommand("ddev", "poweroff") }},
+			{key: "o", label: "Move this project's router to ports 8080 and 8443", command: "ddev config --router-http-port=8080 --router-https-port=8443",
+				run: func(projectPath string) error {
+					return runDDEV(projectPath, "config", "--router-http-port=8080", "--router-https-port=8443")
+				}},
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)mutagen.*(?:error|fail|unable|could not|cannot|timed out|lock)|(?:error|fail).*mutagen`),
+		problem: "Mutagen file sync failed or its daemon is locked",
+		actions: []recoveryAction{
+			{key: "m", label: "Reset Mutagen sync for this project", command: "ddev mutagen reset",
+				run: func(projectPath string) error { return runDDEV(projectPath, "mutagen", "reset") }},
+		},
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)innodb.*(?:corrupt|crash recovery)|volume \S+ (?:is )?(?:corrupt|in use)|failed to (?:mount|create) volume|db container failed|(?:db|database) (?:container|service) .*(?:unhealthy|did not become ready)`),
+		problem: "the project's database volume looks corrupted",
+		actions: []recoveryAction{
+			{key: "v", label: "Remove the project's containers and volumes, database included", command: "ddev stop --remove-data --omit-snapshot",
+				confirm: "This deletes the project's database. Continue?",
+				run: func(projectPath string) error {
+					return runDDEV(projectPath, "stop", "--remove-data", "--omit-snapshot")
+				}},
+		},
+	},
+}
+
+func diagnoseDDEVStart(output string) []ddevFailure {
+	var found []ddevFailure
+	for _, failure := range ddevFailures {
+		if failure.pattern.MatchString(output) {
+			found = append(found, failure)
+		}
+	}
+	return found
+}
+
+func waitForDocker(timeout time.Duration) error {
+	printStatus("Waiting for Docker to start...")
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if exec.Command("docker", "info").Run() == nil {
+			return nil
+		}
+		time.Sleep(2 * time.Second)
+	}
+	return fmt.Errorf("docker did not start within %s", formatDuration(timeout))
+}
+
+func offerDDEVRecovery(projectPath, output string) bool {
+	failures := diagnoseDDEVStart(output)
+	var actions []recoveryAction
+	for _, failure := range failures {
+		printWarning("DDEV could not start: " + failure.problem)
+		for _, action := range failure.actions {
+			if action.available == nil || action.available() {
+				actions = append(actions, action)
+			}
+		}
+	}
+	if len(failures) == 0 {
+		printWarning("The 'ddev start' output matches no known problem; check it above, or run 'ddev debug test'")
+	}
+
+	if !opts.interactive {
+		for _, action := range actions {
+			printWarning(fmt.Sprintf("To fix: %s (%s), then re-run", action.label, action.command))
+		}
+		return false
+	}
+
+	for {
+		fmt.Println()
+		for _, action := range actions {
+			fmt.Printf("  [%s] %s (%s)\n", action.key, action.label, action.command)
+		}
+		fmt.Println("  [r] Retry ddev start")
+		fmt.Println("  [q] Give up")
+		notifyWaitingForInput("DDEV failed to start; choose a fix")
+		key := strings.ToLower(promptKey("Choose an option: "))
+		switch key {
+		case "r":
+			return true
+		case "q", "":
+			return false
+		}
+		for _, action := range actions {
+			if action.key != key {
+				continue
+			}
+			if action.confirm != "" && !confirm(action.confirm, false) {
+				break
+			}
+			if err := action.run(projectPath); err != nil {
+				printError(fmt.Sprintf("%s failed: %v", action.label, err))
+				break
+			}
+			return true
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestDiagnoseDDEVStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{
+			"normal mutagen start",
+			"Starting mutagen sync process... This can take some time.\nMutagen sync flush completed in 4s.\nFor details on sync status 'ddev mutagen st demo -l'\nFailed to start demo: web container exited\n",
+			nil,
+		},
+		{
+			"mutagen sync failure",
+			"Starting mutagen sync process...\nFailed to start demo: mutagen sync session failed: unable to connect to endpoint\n",
+			[]string{"Mutagen file sync failed or its daemon is locked"},
+		},
+		{
+			"mutagen daemon lock",
+			"Error: mutagen daemon lock is held by another process (daemon already running?)\n",
+			[]string{"Mutagen file sync failed or its daemon is locked"},
+		},
+		{
+			"docker not running",
+			"Cannot connect to the Docker daemon at unix:///var/run/docker.sock. Is the docker daemon running?\n",
+			[]string{"Docker is not running"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, failure := range diagnoseDDEVStart(tt.output) {
+				got = append(got, failure.problem)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("got %q, want %q", got, tt.want)
+				}
+			}
+		})
+	}
+}
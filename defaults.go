@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+type userDefaults struct {
+	DockerProvider string   `yaml:"docker_provider"`
+	PHPVersion     string   `yaml:"php_version"`
+	Packages       []string `yaml:"packages"`
+	Modules        []string `yaml:"modules"`
+	Git            struct {
+		Name  string `yaml:"name"`
+		Email string `yaml:"email"`
+	} `yaml:"git"`
+}
+
+func (d userDefaults) overlay(other userDefaults) userDefaults {
+	d.DockerProvider = firstNonEmpty(other.DockerProvider, d.DockerProvider)
+	d.PHPVersion = firstNonEmpty(other.PHPVersion, d.PHPVersion)
+	d.Packages = append(append([]string{}, d.Packages...), other.Packages...)
+	d.Modules = append(append([]string{}, d.Modules...), other.Modules...)
+	d.Git.Name = firstNonEmpty(other.Git.Name, d.Git.Name)
+	d.Git.Email = firstNonEmpty(other.Git.Email, d.Git.Email)
+	return d
+}
+
+var defaults userDefaults
+
+var ddevTelemetry *bool
+
+var phpVersionFlagPattern = regexp.MustCompile(`^\d+\.\d+$`)
+
+func loadUserDefaults() error {
+	settings, err := loadUserSettings()
+	if err != nil {
+		return err
+	}
+	defaults = settings.Defaults
+	ddevTelemetry = settings.Telemetry
+	if opts.policyURL == "" && settings.PolicyURL != "" {
+		opts.policyURL = settings.PolicyURL
+		printStatus(fmt.Sprintf("Enforcing the organization policy from config.yml: %s", opts.policyURL))
+	}
+	if opts.profile != "" {
+		profile, ok := settings.Profiles[opts.profile]
+		if !ok {
+			names := make([]string, 0, len(settings.Profiles))
+			for name := range settings.Profiles {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("unknown profile %q (configured: %s)", opts.profile, firstNonEmpty(strings.Join(names, ", "), "none"))
+		}
+		defaults = defaults.overlay(profile)
+		printStatus(fmt.Sprintf("Using profile %s from config.yml", opts.profile))
+	}
+
+	defaults.DockerProvider = strings.ToLower(defaults.DockerProvider)
+	if defaults.DockerProvider != "" && defaults.DockerProvider != "docker" && defaults.DockerProvider != "colima" {
+		return fmt.Errorf("invalid docker_provider %q in config.yml (expected docker or colima)", defaults.DockerProvider)
+	}
+	if project.DDEV.PHPVersion == "" {
+		project.DDEV.PHPVersion = firstNonEmpty(opts.phpVersion, defaults.PHPVersion)
+	} else if opts.phpVersion != "" {
+		project.DDEV.PHPVersion = opts.phpVersion
+	}
+	if v := project.DDEV.PHPVersion; v != "" && !phpVersionFlagPattern.MatchString(v) {
+		return fmt.Errorf("invalid PHP version %q (expected major.minor, e.g. 8.3)", v)
+	}
+
+	for _, pkg := range defaults.Packages {
+		if !containsPackage(composerPackages, pkg) {
+			composerPackages = append(composerPackages, pkg)
+		}
+	}
+	for _, module := range defaults.Modules {
+		if !containsString(drupalModules, module) {
+			drupalModules = append(drupalModules, module)
+		}
+	}
+	if len(defaults.Modules) > 0 {
+		printStatus(fmt.Sprintf("Adding favorite modules: %s", strings.Join(defaults.Modules, ", ")))
+	}
+	return nil
+}
+
+func configureGitAuthor(projectPath string) error {
+	if defaults.Git.Name == "" && defaults.Git.Email == "" {
+		return nil
+	}
+	if !commandExists("git") {
+		printWarning("git is not installed; skipping git author configuration")
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, ".git")); os.IsNotExist(err) {
+		if err := runCommand("git", "-C", projectPath, "init", "--quiet"); err != nil {
+			return err
+		}
+	}
+	for key, value := range map[string]string{"user.name": defaults.Git.Name, "user.email": defaults.Git.Email} {
+		if value == "" {
+			continue
+		}
+		if err := runCommand("git", "-C", projectPath, "config", key, value); err != nil {
+			return err
+		}
+	}
+	printSuccess("✓ Git author configured for the project repository")
+	return nil
+}
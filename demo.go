@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var demoTour = []tourStop{
+	{path: "/", label: "Umami front page"},
+	{path: "/en/recipes", label: "Recipe listing with search facets"},
+	{path: "/en/articles", label: "Articles"},
+	{path: "/es", label: "Spanish translation of the site"},
+	{path: "/node/add/recipe", label: "Editorial form for a new recipe"},
+	{path: "/admin/content", label: "Content overview"},
+	{path: "/admin/content/media", label: "Media library"},
+	{path: "/admin/structure/block", label: "Block layout"},
+	{path: "/admin/appearance", label: "Themes"},
+}
+
+func siteProfile() string {
+	if opts.demo {
+		return "demo_umami"
+	}
+	return "standard"
+}
+
+func generateDemoContent(projectPath string) error {
+	printStatus("Generating demo content...")
+
+	commands := [][]string{
+		{"drush", "genu", "10", "--roles=content_editor"},
+		{"drush", "gent", "tags", "30"},
+		{"drush", "genm", "20", "--media-types=image"},
+		{"drush", "genc", "30", "--bundles=article,recipe", "--languages=en,es", "--translations=es"},
+	}
+	for _, args := range commands {
+		if err := runDDEV(projectPath, args...); err != nil {
+			printError(fmt.Sprintf("Failed to run %s", strings.Join(args, " ")))
+			return err
+		}
+	}
+
+	printSuccess("✓ Demo content generated")
+	return nil
+}
+
+func printDemoTour(siteURL string) {
+	if siteURL == "" {
+		return
+	}
+	base := strings.TrimSuffix(siteURL, "/")
+
+	stops := append([]tourStop(nil), demoTour...)
+	for _, p := range activePresets {
+		stops = append(stops, p.tour...)
+	}
+
+	fmt.Println()
+	fmt.Println("Demo tour:")
+	for i, stop := range stops {
+		fmt.Printf("%2d. %-45s %s%s\n", i+1, stop.label, base, stop.path)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+)
+
+type devcontainerTemplateData struct {
+	Name       string
+	Docroot    string
+	PHPVersion string
+}
+
+var devcontainerFiles = map[string][][2]string{
+	"ddev": {
+		{"devcontainer-ddev.json", ".devcontainer/devcontainer.json"},
+		{"devcontainer-post-create.sh", ".devcontainer/post-create.sh"},
+	},
+	"php": {
+		{"devcontainer-php.json", ".devcontainer/devcontainer.json"},
+		{"devcontainer-compose.yml", ".devcontainer/docker-compose.yml"},
+		{"devcontainer-Dockerfile", ".devcontainer/Dockerfile"},
+		{"devcontainer-settings.php", "settings.devcontainer.php"},
+	},
+}
+
+func runScaffoldDevcontainer(args []string) int {
+	fs := flag.NewFlagSet("scaffold devcontainer", flag.ContinueOnError)
+	mode := fs.String("mode", "ddev", "ddev (DDEV inside the container, needs docker-in-docker) or php (PHP and MariaDB containers)")
+	path := fs.String("path", ".", "Directory inside the project")
+	force := fs.Bool("force", false, "Overwrite existing files")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	files, ok := devcontainerFiles[*mode]
+	if !ok {
+		printError(fmt.Sprintf("Unknown --mode %q (expected ddev or php)", *mode))
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	data := devcontainerTemplateData{
+		Name:       filepath.Base(projectPath),
+		Docroot:    projectDocroot(projectPath),
+		PHPVersion: projectPHPVersion(projectPath),
+	}
+
+	for _, f := range files {
+		target := filepath.Join(projectPath, filepath.FromSlash(f[1]))
+		if f[1] == "settings.devcontainer.php" {
+			target = filepath.Join(siteDefaultDir(projectPath), f[1])
+		}
+		content, err := renderScaffold(f[0], data)
+		if err != nil {
+			printError(fmt.Sprintf("Failed to render %s: %v", f[0], err))
+			return 1
+		}
+		write := writeScaffoldFile
+		if filepath.Ext(target) == ".sh" {
+			write = writeScaffoldExecutable
+		}
+		if err := write(target, content, *force); err != nil {
+			return 1
+		}
+	}
+
+	fmt.Println()
+	if *mode == "ddev" {
+		fmt.Println("Open the repository in GitHub Codespaces (or 'Reopen in Container' in VS Code); DDEV starts")
+		fmt.Println("inside the container and the site is forwarded on port 8080. Codespaces needs a 4-core machine.")
+	} else {
+		fmt.Println("Open the repository in GitHub Codespaces (or 'Reopen in Container' in VS Code). PHP's built-in")
+		fmt.Println("server serves the site on port 8080; DRUPAL_ENVIRONMENT=devcontainer loads settings.devcontainer.php")
+		fmt.Println("with the MariaDB credentials. Install with 'vendor/bin/drush site:install --existing-config -y'.")
+	}
+	return 0
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const activeConfigHashesPHP = `$h = []; foreach (\Drupal::service('config.storage')->listAll() as $n) { $d = \Drupal::config($n)->getRawData(); unset($d['uuid'], $d['_core']); $h[$n] = md5(serialize($d)); } echo json_encode($h);`
+
+type projectDiff struct {
+	Section string      `json:"section"`
+	OnlyA   []string    `json:"only_a"`
+	OnlyB   []string    `json:"only_b"`
+	Changed [][3]string `json:"changed"`
+	Error   string      `json:"error,omitempty"`
+}
+
+type diffSection struct {
+	title   string
+	collect func(projectPath string) (map[string]string, error)
+}
+
+func (d projectDiff) empty() bool {
+	return len(d.OnlyA) == 0 && len(d.OnlyB) == 0 && len(d.Changed) == 0
+}
+
+func diffMaps(section string, a, b map[string]string) projectDiff {
+	diff := projectDiff{Section: section, OnlyA: []string{}, OnlyB: []string{}, Changed: [][3]string{}}
+	for key, va := range a {
+		vb, ok := b[key]
+		switch {
+		case !ok:
+			diff.OnlyA = append(diff.OnlyA, key)
+		case va != vb:
+			diff.Changed = append(diff.Changed, [3]string{key, va, vb})
+		}
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			diff.OnlyB = append(diff.OnlyB, key)
+		}
+	}
+	sort.Strings(diff.OnlyA)
+	sort.Strings(diff.OnlyB)
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i][0] < diff.Changed[j][0] })
+	return diff
+}
+
+func resolveProjectRef(ref string) (string, error) {
+	if p, ok := findRegisteredProjectByName(ref); ok {
+		return p.Path, nil
+	}
+	path, err := filepath.Abs(ref)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(path, "composer.json")); err != nil {
+		return "", fmt.Errorf("%s is neither a registered project nor a Drupal project directory", ref)
+	}
+	return path, nil
+}
+
+func lockedVersions(projectPath string) (map[string]string, error) {
+	lock, err := loadComposerLock(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	versions := map[string]string{}
+	for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+		version := pkg.Version
+		if strings.HasPrefix(version, "dev-") && pkg.Source.Reference != "" {
+			version += "#" + pkg.Source.Reference[:min(7, len(pkg.Source.Reference))]
+		}
+		versions[pkg.Name] = version
+	}
+	return versions, nil
+}
+
+func enabledModuleVersions(projectPath string) (map[string]string, error) {
+	output, err := ddevOutput(projectPath, "drush", "pm:list", "--status=enabled", "--format=json")
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]struct {
+		Version any `json:"version"`
+	}
+	if err := json.Unmarshal(output, &raw); err != nil {
+		return nil, fmt.Errorf("could not parse drush output: %v", err)
+	}
+	modules := map[string]string{}
+	for name, ext := range raw {
+		modules[name] = ""
+		if ext.Version != nil {
+			modules[name] = fmt.Sprint(ext.Version)
+		}
+	}
+	return modules, nil
+}
+
+func activeConfigHashes(projectPath string) (map[string]string, error) {
+	output, err := ddevOutput(projectPath, "drush", "php:eval", activeConfigHashesPHP)
+	if err != nil {
+		return nil, err
+	}
+	hashes := map[string]string{}
+	if err := json.Unmarshal(output, &hashes); err != nil {
+		return nil, fmt.Errorf("could not parse drush output: %v", err)
+	}
+	delete(hashes, "core.extension")
+	return hashes, nil
+}
+
+func environmentFacts(projectPath string) (map[string]string, error) {
+	describe, err := ddevDescribe(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	facts := map[string]string{}
+	for _, key := range []string{"php_version", "database_type", "database_version", "webserver_type", "nodejs_version"} {
+		if v, ok := describe[key]; ok && v != nil {
+			facts[key] = fmt.Sprint(v)
+		}
+	}
+	return facts, nil
+}
+
+func printProjectDiff(d projectDiff, nameA, nameB string) {
+	fmt.Println()
+	if d.Error != "" {
+		printWarning(fmt.Sprintf("%s: skipped (%s)", d.Section, d.Error))
+		return
+	}
+	if d.empty() {
+		printSuccess(fmt.Sprintf("✓ %s: identical", d.Section))
+		return
+	}
+	printStatus(fmt.Sprintf("%s: %d only in %s, %d only in %s, %d different",
+		d.Section, len(d.OnlyA), nameA, len(d.OnlyB), nameB, len(d.Changed)))
+	for _, item := range d.OnlyA {
+		fmt.Printf("  - %s (only in %s)\n", item, nameA)
+	}
+	for _, item := range d.OnlyB {
+		fmt.Printf("  + %s (only in %s)\n", item, nameB)
+	}
+	for _, c := range d.Changed {
+		if d.Section == "Config" {
+			fmt.Printf("  ~ %s\n", c[0])
+			continue
+		}
+		fmt.Printf("  ~ %s: %s → %s\n", c[0], c[1], c[2])
+	}
+}
+
+func runDiffProjects(args []string) int {
+	fs := flag.NewFlagSet("diff-projects", flag.ContinueOnError)
+	jsonOutput := fs.Bool("json", false, "Print the report as JSON")
+	skipConfig := fs.Bool("skip-config", false, "Do not compare active configuration")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 2 {
+		printError("Usage: install-drupal diff-projects [flags] <project-a> <project-b>")
+		return 2
+	}
+	paths := [2]string{}
+	for i := range paths {
+		path, err := resolveProjectRef(fs.Arg(i))
+		if err != nil {
+			printError(err.Error())
+			return 2
+		}
+		paths[i] = path
+	}
+	nameA, nameB := filepath.Base(paths[0]), filepath.Base(paths[1])
+	if nameA == nameB {
+		nameA, nameB = paths[0], paths[1]
+	}
+
+	sections := []diffSection{
+		{"Environment", environmentFacts},
+		{"Composer packages", lockedVersions},
+		{"Enabled modules", enabledModuleVersions},
+	}
+	if !*skipConfig {
+		sections = append(sections, diffSection{"Config", activeConfigHashes})
+	}
+
+	var report []projectDiff
+	for _, section := range sections {
+		if !*jsonOutput {
+			printStatus(fmt.Sprintf("Comparing %s...", strings.ToLower(section.title)))
+		}
+		a, errA := section.collect(paths[0])
+		b, errB := section.collect(paths[1])
+		if errA != nil || errB != nil {
+			failed := []string{}
+			if errA != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", nameA, errA))
+			}
+			if errB != nil {
+				failed = append(failed, fmt.Sprintf("%s: %v", nameB, errB))
+			}
+			report = append(report, projectDiff{Section: section.title, Error: strings.Join(failed, "; ")})
+			continue
+		}
+		report = append(report, diffMaps(section.title, a, b))
+	}
+
+	differs := false
+	for _, d := range report {
+		if !d.empty() {
+			differs = true
+		}
+	}
+	if *jsonOutput {
+		data, err := json.MarshalIndent(map[string]any{"a": paths[0], "b": paths[1], "sections": report}, "", "  ")
+		if err != nil {
+			printError(err.Error())
+			return 1
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, d := range report {
+			printProjectDiff(d, nameA, nameB)
+		}
+		fmt.Println()
+		if differs {
+			printWarning(fmt.Sprintf("%s and %s differ", nameA, nameB))
+		} else {
+			printSuccess("✓ No differences found")
+		}
+	}
+	if differs {
+		return 1
+	}
+	return 0
+}
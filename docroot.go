@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var docrootCandidates = []string{"web", "docroot", "html"}
+
+var ddevDocrootPattern = regexp.MustCompile(`(?m)^docroot:\s*["']?([^"'\s#]*)`)
+
+func detectDocroot(projectPath string) string {
+	if composer, err := readJSONFile(filepath.Join(projectPath, "composer.json")); err == nil {
+		if extra, ok := composer.values["extra"].(*jsonObject); ok {
+			if scaffold, ok := extra.values["drupal-scaffold"].(*jsonObject); ok {
+				if locations, ok := scaffold.values["locations"].(*jsonObject); ok {
+					if root, ok := locations.values["web-root"].(string); ok {
+						root = strings.Trim(strings.TrimPrefix(root, "./"), "/")
+						if root != "" {
+							return root
+						}
+					}
+				}
+			}
+		}
+	}
+	for _, dir := range docrootCandidates {
+		for _, marker := range []string{"index.php", "core", "sites"} {
+			if _, err := os.Stat(filepath.Join(projectPath, dir, marker)); err == nil {
+				return dir
+			}
+		}
+	}
+	return "web"
+}
+
+func detectDrupalProjectType(projectPath string) string {
+	major := ""
+	if versions, err := readComposerLock(projectPath); err == nil {
+		for _, pkg := range []string{"drupal/core", "drupal/core-recommended"} {
+			if v, ok := versions[pkg]; ok {
+				major = leadingDigits(v)
+				break
+			}
+		}
+	}
+	if major == "" {
+		if composer, err := readJSONFile(filepath.Join(projectPath, "composer.json")); err == nil {
+			if require, ok := composer.values["require"].(*jsonObject); ok {
+				for _, pkg := range []string{"drupal/core-recommended", "drupal/core", "drupal/core-dev"} {
+					if constraint, ok := require.values[pkg].(string); ok {
+						major = leadingDigits(strings.TrimLeft(constraint, "^~>=v "))
+						break
+					}
+				}
+			}
+		}
+	}
+	switch major {
+	case "7", "8", "9", "10", "11":
+		return "drupal" + major
+	case "":
+		return "drupal11"
+	}
+	return "drupal"
+}
+
+func projectDocroot(projectPath string) string {
+	if data, err := os.ReadFile(filepath.Join(projectPath, ".ddev", "config.yaml")); err == nil {
+		if m := ddevDocrootPattern.FindSubmatch(data); m != nil && len(m[1]) > 0 {
+			return string(m[1])
+		}
+	}
+	if opts.docroot != "" {
+		return opts.docroot
+	}
+	return detectDocroot(projectPath)
+}
+
+func siteDefaultDir(projectPath string) string {
+	return filepath.Join(projectPath, projectDocroot(projectPath), "sites", "default")
+}
+
+func siteDefaultFile(projectPath, name string) string {
+	return projectDocroot(projectPath) + "/sites/default/" + name
+}
+
+func ddevProjectSettings(projectPath string) (projectType, docroot string) {
+	projectType, docroot = opts.projectType, opts.docroot
+	if projectType == "" {
+		projectType = detectDrupalProjectType(projectPath)
+	}
+	if docroot == "" {
+		docroot = detectDocroot(projectPath)
+	}
+	if opts.projectType == "" || opts.docroot == "" {
+		printStatus(fmt.Sprintf("Using DDEV project type %s with docroot %s/", projectType, docroot))
+	}
+	return projectType, docroot
+}
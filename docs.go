@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed runbooks/*.md.tmpl
+var embeddedRunbooks embed.FS
+
+type runbookCommand struct {
+	Name, Description string
+}
+
+type runbookEnvKey struct {
+	Name, Description string
+}
+
+type runbookData struct {
+	ProjectName  string
+	RepoDir      string
+	Generated    string
+	SiteURL      string
+	ExtraURLs    []string
+	Docroot      string
+	PHPVersion   string
+	Database     string
+	BasicAuth    bool
+	Commands     []runbookCommand
+	Environments []string
+	EnvKeys      []runbookEnvKey
+}
+
+func ddevDescribe(projectPath string) (map[string]any, error) {
+	cmd := exec.Command("ddev", "describe", "--json-output")
+	cmd.Dir = projectPath
+	if dryRunCommand(cmd) {
+		return nil, errDryRun
+	}
+	output, err := commandOutput(cmd)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]any
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil, err
+	}
+	switch raw := result["raw"].(type) {
+	case map[string]any:
+		return raw, nil
+	case []any:
+		if len(raw) > 0 {
+			if project, ok := raw[0].(map[string]any); ok {
+				return project, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unexpected ddev describe output")
+}
+
+func ddevCommandDescriptions() []runbookCommand {
+	var commands []runbookCommand
+	fs.WalkDir(embeddedDDEVCommands, "ddev/commands", func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		data, err := embeddedDDEVCommands.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		for _, line := range strings.Split(string(data), "\n") {
+			if desc, ok := strings.CutPrefix(line, "## Description:"); ok {
+				commands = append(commands, runbookCommand{Name: d.Name(), Description: strings.TrimSpace(desc)})
+				break
+			}
+		}
+		return nil
+	})
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+	return commands
+}
+
+func envExampleKeys(path string) []runbookEnvKey {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var keys []runbookEnvKey
+	comment := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if text, ok := strings.CutPrefix(line, "#"); ok {
+			comment = strings.TrimSpace(text)
+			continue
+		}
+		if key, _, ok := strings.Cut(line, "="); ok {
+			keys = append(keys, runbookEnvKey{Name: strings.TrimSpace(key), Description: comment})
+		}
+		comment = ""
+	}
+	return keys
+}
+
+func collectRunbookData(projectPath string) runbookData {
+	data := runbookData{
+		ProjectName: filepath.Base(projectPath),
+		RepoDir:     filepath.Base(projectPath),
+		Generated:   time.Now().Format(time.DateOnly),
+		Docroot:     projectDocroot(projectPath),
+		PHPVersion:  "(see .ddev/config.yaml)",
+		Database:    "(see .ddev/config.yaml)",
+		Commands:    ddevCommandDescriptions(),
+	}
+
+	if describe, err := ddevDescribe(projectPath); err == nil {
+		if name, ok := describe["name"].(string); ok && name != "" {
+			data.ProjectName = name
+		}
+		data.SiteURL, _ = describe["primary_url"].(string)
+		if data.SiteURL == "" {
+			data.SiteURL, _ = describe["https_url"].(string)
+		}
+		if urls, ok := describe["urls"].([]any); ok {
+			for _, u := range urls {
+				if s, ok := u.(string); ok && s != data.SiteURL && strings.HasPrefix(s, "https://") {
+					data.ExtraURLs = append(data.ExtraURLs, s)
+				}
+			}
+		}
+		if docroot, ok := describe["docroot"].(string); ok && docroot != "" {
+			data.Docroot = docroot
+		}
+		if php, ok := describe["php_version"].(string); ok {
+			data.PHPVersion = php
+		}
+		dbType, _ := describe["database_type"].(string)
+		dbVersion, _ := describe["database_version"].(string)
+		if dbType != "" {
+			data.Database = strings.TrimSpace(dbType + " " + dbVersion)
+		}
+	} else {
+		printWarning("Could not read 'ddev describe'; runbooks will not include URLs or versions")
+	}
+	if data.SiteURL == "" {
+		data.SiteURL = "https://" + data.ProjectName + ".ddev.site"
+	}
+
+	if _, err := os.Stat(filepath.Join(projectPath, ".ddev", "nginx", ".htpasswd")); err == nil {
+		data.BasicAuth = true
+	}
+	entries, _ := fs.ReadDir(embeddedSettingsFiles, "settings")
+	for _, entry := range entries {
+		env, ok := strings.CutPrefix(strings.TrimSuffix(entry.Name(), ".php"), "settings.")
+		if ok && env != "local" {
+			data.Environments = append(data.Environments, env)
+		}
+	}
+	_, examplePath := dotEnvPaths(projectPath)
+	data.EnvKeys = envExampleKeys(examplePath)
+	return data
+}
+
+func writeRunbooks(projectPath string) error {
+	printStatus("Writing project runbooks to docs/...")
+	data := collectRunbookData(projectPath)
+
+	entries, err := fs.ReadDir(embeddedRunbooks, "runbooks")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		body, err := embeddedRunbooks.ReadFile("runbooks/" + entry.Name())
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(entry.Name(), ".tmpl")
+		tmpl, err := template.New(name).Option("missingkey=error").Parse(string(body))
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, data); err != nil {
+			printError(fmt.Sprintf("Failed to render %s", name))
+			return err
+		}
+		if err := writeFile(filepath.Join(projectPath, "docs", name), buf.Bytes()); err != nil {
+			printError(fmt.Sprintf("Failed to write docs/%s", name))
+			return err
+		}
+	}
+
+	printSuccess(fmt.Sprintf("✓ Runbooks written to %s", filepath.Join(projectPath, "docs")))
+	return nil
+}
+
+func runDocs(args []string) int {
+	fs := flag.NewFlagSet("docs", flag.ContinueOnError)
+	path := fs.String("path", ".", "Path to the DDEV project")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if err := writeRunbooks(projectPath); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const dotEnvLoaderFile = "settings.env.php"
+
+const dotEnvLoader = `<?php
+
+/**
+ * @file
+ * Loads the project's .env file into the environment.
+ *
+ * Managed by drupal-scripts ('install-drupal env'). Variables that are already
+ * set in the real environment take precedence over the file, so hosting
+ * platforms can provide their own values.
+ */
+
+$drupal_scripts_env = __DIR__ . '/%s';
+if (is_readable($drupal_scripts_env)) {
+  foreach (file($drupal_scripts_env, FILE_IGNORE_NEW_LINES | FILE_SKIP_EMPTY_LINES) as $line) {
+    $line = trim($line);
+    if ($line === '' || $line[0] === '#' || strpos($line, '=') === FALSE) {
+      continue;
+    }
+    [$name, $value] = array_map('trim', explode('=', preg_replace('/^export\s+/', '', $line), 2));
+    if (strlen($value) > 1 && ($value[0] === '"' || $value[0] === "'") && substr($value, -1) === $value[0]) {
+      $value = substr($value, 1, -1);
+      $value = $line[strpos($line, '=') + 1] === '"' ? stripcslashes($value) : $value;
+    }
+    if (getenv($name) === FALSE) {
+      putenv("$name=$value");
+      $_ENV[$name] = $_SERVER[$name] = $value;
+    }
+  }
+}
+unset($drupal_scripts_env, $line, $name, $value);
+`
+
+const dotEnvLoaderInclude = "if (file_exists(__DIR__ . '/" + dotEnvLoaderFile + "')) {\n  require __DIR__ . '/" + dotEnvLoaderFile + "';\n}"
+
+const dotEnvOverrides = `if (getenv('DRUPAL_HASH_SALT')) {
+  $settings['hash_salt'] = getenv('DRUPAL_HASH_SALT');
+}
+if (getenv('DRUPAL_ENVIRONMENT')) {
+  $settings['environment'] = getenv('DRUPAL_ENVIRONMENT');
+}`
+
+type dotEnvKey struct {
+	name        string
+	description string
+	value       func(environment string) (string, error)
+}
+
+var managedDotEnvKeys = []dotEnvKey{
+	{name: "DRUPAL_ENVIRONMENT", description: "Environment name: local, dev, stage or prod", value: func(environment string) (string, error) {
+		return environment, nil
+	}},
+	{name: "DRUPAL_HASH_SALT", description: "Per-environment salt for one-time login links and form tokens", value: func(string) (string, error) {
+		return randomString(apr1Alphabet[2:], 64)
+	}},
+}
+
+func dotEnvPaths(projectPath string) (string, string) {
+	return filepath.Join(projectPath, ".env"), filepath.Join(projectPath, ".env.example")
+}
+
+func writeDotEnvExample(path string, keys []string, descriptions map[string]string) error {
+	existing, err := readEnvFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	data, _ := os.ReadFile(path)
+	out := string(data)
+	if out == "" {
+		out = "# Copy to .env and fill in. Managed by 'install-drupal env'.\n"
+	}
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	for _, key := range keys {
+		if _, ok := existing[key]; ok {
+			continue
+		}
+		if desc := descriptions[key]; desc != "" {
+			out += "# " + desc + "\n"
+		}
+		out += key + "=\n"
+	}
+	return writeFile(path, []byte(out))
+}
+
+func installDotEnvLoader(projectPath string) error {
+	siteDir := siteDefaultDir(projectPath)
+	envPath, _ := dotEnvPaths(projectPath)
+	rel, err := filepath.Rel(siteDir, envPath)
+	if err != nil {
+		return err
+	}
+	loaderPath := filepath.Join(siteDir, dotEnvLoaderFile)
+	if err := writeFile(loaderPath, []byte(fmt.Sprintf(dotEnvLoader, filepath.ToSlash(rel)))); err != nil {
+		printError(fmt.Sprintf("Failed to write %s", dotEnvLoaderFile))
+		return err
+	}
+	if err := prependToSettings(projectPath, "Load the project .env file (install-drupal env).", dotEnvLoaderInclude); err != nil {
+		return err
+	}
+	return appendToSettings(projectPath, "Settings managed through .env (install-drupal env).", dotEnvOverrides)
+}
+
+func initDotEnv(projectPath, environment string) error {
+	printStatus("Setting up .env...")
+
+	envPath, examplePath := dotEnvPaths(projectPath)
+	values := map[string]string{}
+	descriptions := map[string]string{}
+	var keys []string
+	for _, k := range managedDotEnvKeys {
+		value, err := k.value(environment)
+		if err != nil {
+			return err
+		}
+		values[k.name] = value
+		descriptions[k.name] = k.description
+		keys = append(keys, k.name)
+	}
+
+	if err := appendDotEnv(envPath, values, keys); err != nil {
+		printError(fmt.Sprintf("Failed to write %s", envPath))
+		return err
+	}
+	if err := writeDotEnvExample(examplePath, keys, descriptions); err != nil {
+		printError(fmt.Sprintf("Failed to write %s", examplePath))
+		return err
+	}
+	if err := ensureGitignored(projectPath, ".env"); err != nil {
+		printWarning(fmt.Sprintf("Could not add .env to .gitignore: %v", err))
+	}
+	if err := installDotEnvLoader(projectPath); err != nil {
+		return err
+	}
+
+	printSuccess("✓ .env and .env.example ready; settings.php loads .env")
+	return nil
+}
+
+func runEnvInit(args []string) int {
+	fs := flag.NewFlagSet("env init", flag.ContinueOnError)
+	path := fs.String("path", ".", "Path to the Drupal project")
+	environment := fs.String("environment", "local", "Value for DRUPAL_ENVIRONMENT in a new .env")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if err := initDotEnv(projectPath, *environment); err != nil {
+		return 1
+	}
+	return 0
+}
+
+func runEnvSet(args []string) int {
+	fs := flag.NewFlagSet("env set", flag.ContinueOnError)
+	path := fs.String("path", ".", "Path to the Drupal project")
+	description := fs.String("description", "", "Comment to add to .env.example for new keys")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		printError("Usage: install-drupal env set KEY=VALUE [KEY=VALUE...]")
+		return 2
+	}
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	envPath, examplePath := dotEnvPaths(projectPath)
+	values, err := readEnvFile(envPath)
+	if err != nil && !os.IsNotExist(err) {
+		printError(err.Error())
+		return 1
+	}
+	if values == nil {
+		values = map[string]string{}
+	}
+	var keys []string
+	descriptions := map[string]string{}
+	for _, arg := range fs.Args() {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || envVarName(key) != key {
+			printError(fmt.Sprintf("Invalid assignment %q (expected UPPER_CASE_KEY=value)", arg))
+			return 2
+		}
+		values[key] = value
+		descriptions[key] = *description
+		keys = append(keys, key)
+	}
+
+	if err := writeDotEnv(envPath, values); err != nil {
+		printError(fmt.Sprintf("Failed to write %s: %v", envPath, err))
+		return 1
+	}
+	if err := writeDotEnvExample(examplePath, keys, descriptions); err != nil {
+		printError(fmt.Sprintf("Failed to write %s: %v", examplePath, err))
+		return 1
+	}
+	printSuccess(fmt.Sprintf("✓ Set %s in .env", strings.Join(keys, ", ")))
+	return 0
+}
+
+func writeDotEnv(path string, values map[string]string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	written := map[string]bool{}
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(string(data), "\n"), "\n") {
+		key, _, ok := strings.Cut(strings.TrimPrefix(strings.TrimSpace(line), "export "), "=")
+		key = strings.TrimSpace(key)
+		if value, managed := values[key]; ok && managed && !strings.HasPrefix(key, "#") {
+			line = key + "=" + dotenvQuote(value)
+			written[key] = true
+		}
+		if line != "" || len(lines) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	var added []string
+	for key := range values {
+		if !written[key] {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(added)
+	for _, key := range added {
+		lines = append(lines, key+"="+dotenvQuote(values[key]))
+	}
+	return writeSecretFile(path, []byte(strings.Join(lines, "\n")+"\n"))
+}
+
+func runEnvCheck(args []string) int {
+	fs := flag.NewFlagSet("env check", flag.ContinueOnError)
+	path := fs.String("path", ".", "Path to the Drupal project")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	envPath, examplePath := dotEnvPaths(projectPath)
+	expected, err := readEnvFile(examplePath)
+	if err != nil {
+		printError(fmt.Sprintf("Cannot read %s: %v", examplePath, err))
+		return 1
+	}
+	actual, err := readEnvFile(envPath)
+	if err != nil && !os.IsNotExist(err) {
+		printError(err.Error())
+		return 1
+	}
+
+	var missing []string
+	for key := range expected {
+		if actual[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	sort.Strings(missing)
+	if len(missing) > 0 {
+		printError(fmt.Sprintf("Missing or empty in .env: %s", strings.Join(missing, ", ")))
+		return 1
+	}
+	printSuccess(fmt.Sprintf("✓ .env provides all %d keys from .env.example", len(expected)))
+	return 0
+}
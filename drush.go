@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var ddevTypePattern = regexp.MustCompile(`(?m)^type:\s*["']?([^"'\s#]*)`)
+
+var (
+	drushReady   = map[string]bool{}
+	drushReadyMu sync.Mutex
+)
+
+var drushAutoInstall bool
+
+func ddevHasDrushCommand(projectPath string) bool {
+	data, err := os.ReadFile(filepath.Join(projectPath, ".ddev", "config.yaml"))
+	if err != nil {
+		return true
+	}
+	m := ddevTypePattern.FindSubmatch(data)
+	if m == nil {
+		return true
+	}
+	projectType := string(m[1])
+	return strings.HasPrefix(projectType, "drupal") || projectType == "backdrop"
+}
+
+func drushInComposerJSON(projectPath string) bool {
+	composer, err := readJSONFile(filepath.Join(projectPath, "composer.json"))
+	if err != nil {
+		return false
+	}
+	for _, section := range []string{"require", "require-dev"} {
+		if packages, ok := composer.values[section].(*jsonObject); ok {
+			if _, ok := packages.values["drush/drush"]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func ensureDrush(projectPath string) error {
+	drushReadyMu.Lock()
+	defer drushReadyMu.Unlock()
+	if drushReady[projectPath] {
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, "vendor", "bin", "drush")); err == nil {
+		drushReady[projectPath] = true
+		return nil
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, ".ddev")); err != nil {
+		return nil
+	}
+
+	args := []string{"composer", "install"}
+	question := "drush is not installed yet. Run 'ddev composer install' now?"
+	if !drushInComposerJSON(projectPath) {
+		if lockSnapshot != "" {
+			return fmt.Errorf("drush is not installed and the lock snapshot does not include drush/drush")
+		}
+		args = []string{"composer", "require", "drush/drush"}
+		question = "drush/drush is not required by this project. Add it with 'ddev composer require drush/drush'?"
+	}
+	if !drushAutoInstall {
+		if !opts.interactive {
+			return fmt.Errorf("drush is not installed; run 'ddev %s' first", strings.Join(args, " "))
+		}
+		if !confirm(question, false) {
+			return fmt.Errorf("drush is not installed")
+		}
+	} else if args[1] == "require" {
+		printWarning("drush/drush is not required by this project; adding it")
+	} else {
+		printWarning("drush is not installed yet; running composer install first")
+	}
+	cmd := exec.Command("ddev", args...)
+	cmd.Dir = projectPath
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	if err := runCmd(cmd); err != nil {
+		return fmt.Errorf("could not install drush (%s): %v", strings.Join(args, " "), err)
+	}
+	drushReady[projectPath] = true
+	return nil
+}
+
+func ddevCommand(projectPath string, args ...string) *exec.Cmd {
+	if len(args) > 0 && args[0] == "drush" {
+		if err := ensureDrush(projectPath); err != nil {
+			printWarning(err.Error())
+		}
+		if !ddevHasDrushCommand(projectPath) {
+			args = append([]string{"exec", "--dir", containerProjectRoot, "vendor/bin/drush"}, args[1:]...)
+		}
+	}
+	cmd := exec.Command("ddev", args...)
+	cmd.Dir = projectPath
+	return cmd
+}
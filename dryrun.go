@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+var errDryRun = errors.New("not run in a dry run")
+
+func printDryRun(msg string) {
+	printLabeled(colorYellow, "DRY-RUN", msg)
+}
+
+func shellQuote(arg string) string {
+	if arg != "" && strings.Trim(arg, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_=+./:,@^~%") == "" {
+		return arg
+	}
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+func commandLine(cmd *exec.Cmd) string {
+	if cmd.Dir == "" {
+		return commandArgs(cmd)
+	}
+	return "(cd " + shellQuote(cmd.Dir) + " && " + commandArgs(cmd) + ")"
+}
+
+func commandArgs(cmd *exec.Cmd) string {
+	name := filepath.Base(cmd.Path)
+	if len(cmd.Args) > 0 {
+		name = cmd.Args[0]
+	}
+	parts := []string{shellQuote(name)}
+	for _, arg := range cmd.Args[1:] {
+		if strings.HasPrefix(arg, "--account-pass=") {
+			arg = "--account-pass=********"
+		}
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " ")
+}
+
+func dryRunCommand(cmd *exec.Cmd) bool {
+	if !opts.dryRun {
+		return false
+	}
+	printDryRun(commandLine(cmd))
+	return true
+}
+
+func runCmd(cmd *exec.Cmd) error {
+	if dryRunCommand(cmd) {
+		return nil
+	}
+	return startCommand(cmd, nil)
+}
+
+func runCmdCapture(cmd *exec.Cmd) (string, error) {
+	if dryRunCommand(cmd) {
+		return "", nil
+	}
+	var output lockedBuffer
+	err := startCommand(cmd, &output)
+	return string(output.Bytes()), err
+}
+
+func dryRunFile(action, path string) bool {
+	if !opts.dryRun {
+		return false
+	}
+	printDryRun(action + " " + path)
+	return true
+}
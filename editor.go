@@ -0,0 +1,69 @@
+package main
+
+const paragraphsDisplaysPHP = `$repo = \Drupal::service('entity_display.repository');
+$repo->getFormDisplay('node', 'page')->setComponent('field_components', ['type' => 'paragraphs', 'weight' => 5])->save();
+$repo->getViewDisplay('node', 'page')->setComponent('field_components', ['type' => 'entity_reference_revisions_entity_view', 'label' => 'hidden', 'weight' => 5])->save();`
+
+const layoutBuilderPHP = `use Drupal\layout_builder\Section;
+$display = \Drupal::service('entity_display.repository')->getViewDisplay('node', 'page');
+$display->enableLayoutBuilder()->setOverridable();
+if (count($display->getSections()) < 2) {
+  $display->appendSection(new Section('layout_twocol_section', ['label' => 'Two columns', 'column_widths' => '67-33']));
+}
+$display->setThirdPartySetting('layout_builder_restrictions', 'allowed_block_categories', []);
+$display->setThirdPartySetting('layout_builder_restrictions', 'entity_view_mode_restriction', [
+  'allowed_layouts' => ['layout_onecol', 'layout_twocol_section', 'layout_threecol_section'],
+  'denylisted_blocks' => [],
+  'allowlisted_blocks' => [],
+  'restricted_categories' => [],
+]);
+$display->save();`
+
+func init() {
+	registerPreset(&preset{
+		name:        "ckeditor",
+		description: "CKEditor 5 Basic/Full HTML formats with Linkit and Editor Advanced Link",
+		packages:    []string{"drupal/linkit", "drupal/editor_advanced_link"},
+		modules:     []string{"linkit", "editor_advanced_link"},
+		tour: []tourStop{
+			{path: "/admin/config/content/formats/manage/basic_html", label: "Basic HTML text format and CKEditor toolbar"},
+		},
+	})
+
+	registerPreset(&preset{
+		name:        "paragraphs",
+		description: "page building with Paragraphs: text and call-to-action paragraph types on basic pages",
+		packages:    []string{"drupal/paragraphs", "drupal/entity_reference_revisions"},
+		modules:     []string{"paragraphs", "entity_reference_revisions", "link"},
+		excludes:    []string{"layout-builder"},
+		tour: []tourStop{
+			{path: "/admin/structure/paragraphs_type", label: "Paragraph types"},
+			{path: "/node/add/page", label: "Basic page with paragraph components"},
+		},
+		apply: func(projectPath string) error {
+			return applyDisplayPHP(projectPath, paragraphsDisplaysPHP)
+		},
+	})
+
+	registerPreset(&preset{
+		name:        "layout-builder",
+		description: "page building with Layout Builder: per-page layouts on basic pages, restricted to 1-3 column layouts",
+		packages:    []string{"drupal/layout_builder_restrictions"},
+		modules:     []string{"layout_discovery", "layout_builder", "layout_builder_restrictions"},
+		excludes:    []string{"paragraphs"},
+		tour: []tourStop{
+			{path: "/admin/structure/types/manage/page/display/default/layout", label: "Default basic page layout"},
+		},
+		apply: func(projectPath string) error {
+			return applyDisplayPHP(projectPath, layoutBuilderPHP)
+		},
+	})
+}
+
+func applyDisplayPHP(projectPath, php string) error {
+	if err := runDDEVQuiet(projectPath, "drush", "php:eval", php); err != nil {
+		printError("Failed to update basic page displays")
+		return err
+	}
+	return exportConfig(projectPath, "core.entity_form_display.node.page.default", "core.entity_view_display.node.page.default")
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	settingsAssignPattern = regexp.MustCompile(`^(\s*)(\$(?:settings|config|databases)((?:\[\s*'[^']*'\s*\])+))\s*=\s*'([^']*)'\s*;`)
+	settingsArrayPattern  = regexp.MustCompile(`'([A-Za-z0-9_.]+)'\s*=>\s*'([^']*)'`)
+	settingsStartPattern  = regexp.MustCompile(`^\s*\$(settings|config|databases)((?:\[\s*'[^']*'\s*\])*)\s*=\s*(?:\[|array\()`)
+	settingsKeyPattern    = regexp.MustCompile(`'([^']*)'`)
+	sensitiveKeyPattern   = regexp.MustCompile(`(?i)(pass(word)?|secret|token|api_?key|access_?key|private_?key|hash_salt|credentials?)$`)
+	envNameReplacer       = regexp.MustCompile(`[^A-Z0-9]+`)
+)
+
+type hardcodedSecret struct {
+	file    string
+	line    int
+	key     string
+	value   string
+	offset  int
+	envName string
+}
+
+func envVarName(parts ...string) string {
+	name := strings.ToUpper(strings.Join(parts, "_"))
+	return strings.Trim(envNameReplacer.ReplaceAllString(name, "_"), "_")
+}
+
+func settingsKeys(index string) []string {
+	var keys []string
+	for _, m := range settingsKeyPattern.FindAllStringSubmatch(index, -1) {
+		keys = append(keys, m[1])
+	}
+	return keys
+}
+
+func maskSecret(value string) string {
+	if len(value) <= 4 {
+		return strings.Repeat("*", len(value))
+	}
+	return value[:2] + strings.Repeat("*", len(value)-4) + value[len(value)-2:]
+}
+
+func scanSettingsFile(path string) ([]hardcodedSecret, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []hardcodedSecret
+	var context []string
+	for i, line := range strings.Split(string(data), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "*") {
+			continue
+		}
+		if m := settingsAssignPattern.FindStringSubmatchIndex(line); m != nil {
+			keys := settingsKeys(line[m[6]:m[7]])
+			value := line[m[8]:m[9]]
+			if len(keys) > 0 && sensitiveKeyPattern.MatchString(keys[len(keys)-1]) && value != "" {
+				found = append(found, hardcodedSecret{file: path, line: i + 1, key: line[m[4]:m[5]], value: value, offset: m[8] - 1, envName: envVarName(keys...)})
+			}
+			continue
+		}
+		if m := settingsStartPattern.FindStringSubmatch(line); m != nil {
+			context = append([]string{m[1]}, settingsKeys(m[2])...)
+			continue
+		}
+		if context != nil {
+			for _, m := range settingsArrayPattern.FindAllStringSubmatchIndex(line, -1) {
+				key, value := line[m[2]:m[3]], line[m[4]:m[5]]
+				if sensitiveKeyPattern.MatchString(key) && value != "" {
+					keys := append(append([]string(nil), context...), key)
+					found = append(found, hardcodedSecret{file: path, line: i + 1, key: strings.Join(keys, "."), value: value, offset: m[4] - 1, envName: envVarName(keys...)})
+				}
+			}
+		}
+		if strings.HasSuffix(trimmed, "];") || strings.HasSuffix(trimmed, ");") {
+			context = nil
+		}
+	}
+	return found, nil
+}
+
+func ddevManaged(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), "#ddev-generated")
+}
+
+func settingsFiles(projectPath string) []string {
+	matches, _ := filepath.Glob(filepath.Join(siteDefaultDir(projectPath), "settings*.php"))
+	sort.Strings(matches)
+	return matches
+}
+
+func rewriteSecrets(path string, secrets []hardcodedSecret) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	lines := strings.Split(string(data), "\n")
+	sorted := append([]hardcodedSecret(nil), secrets...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].line != sorted[j].line {
+			return sorted[i].line < sorted[j].line
+		}
+		return sorted[i].offset > sorted[j].offset
+	})
+	for _, s := range sorted {
+		idx := s.line - 1
+		literal := "'" + s.value + "'"
+		end := s.offset + len(literal)
+		if idx < 0 || idx >= len(lines) || end > len(lines[idx]) || lines[idx][s.offset:end] != literal {
+			return fmt.Errorf("line %d changed since the scan", s.line)
+		}
+		lines[idx] = lines[idx][:s.offset] + "getenv('" + s.envName + "')" + lines[idx][end:]
+	}
+	return writeFile(path, []byte(strings.Join(lines, "\n")))
+}
+
+func appendDotEnv(path string, values map[string]string, keys []string) error {
+	existing, err := readEnvFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	data, _ := os.ReadFile(path)
+	out := string(data)
+	if out != "" && !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	for _, key := range keys {
+		if _, ok := existing[key]; ok {
+			continue
+		}
+		out += key + "=" + dotenvQuote(values[key]) + "\n"
+	}
+	return writeSecretFile(path, []byte(out))
+}
+
+func dotenvQuote(value string) string {
+	if !strings.ContainsAny(value, "'\n") {
+		return "'" + value + "'"
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`).Replace(value)
+	return `"` + escaped + `"`
+}
+
+func ensureGitignored(projectPath, entry string) error {
+	path := filepath.Join(projectPath, ".gitignore")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == entry || strings.TrimSpace(line) == "/"+entry {
+			return nil
+		}
+	}
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return writeFile(path, []byte(content+"/"+entry+"\n"))
+}
+
+func runEnv(args []string) int {
+	if len(args) == 0 {
+		printError("Usage: install-drupal env <init|set|check|audit|list|use> [flags]")
+		return 2
+	}
+	switch args[0] {
+	case "init":
+		return runEnvInit(args[1:])
+	case "set":
+		return runEnvSet(args[1:])
+	case "check":
+		return runEnvCheck(args[1:])
+	case "audit":
+		return runEnvAudit(args[1:])
+	case "list":
+		return runEnvList(args[1:])
+	case "use":
+		return runEnvUse(args[1:])
+	}
+	printError(fmt.Sprintf("Unknown env command %q", args[0]))
+	return 2
+}
+
+func runEnvAudit(args []string) int {
+	fs := flag.NewFlagSet("env audit", flag.ContinueOnError)
+	path := fs.String("path", ".", "Path to the Drupal project")
+	fix := fs.Bool("fix", false, "Replace hardcoded values with getenv() and write them to the project .env loaded by settings.php (not .ddev/.env, which only DDEV reads)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	files := settingsFiles(projectPath)
+	if len(files) == 0 {
+		printError(fmt.Sprintf("No settings files found in %s", siteDefaultDir(projectPath)))
+		return 1
+	}
+
+	var fixable []hardcodedSecret
+	total := 0
+	for _, file := range files {
+		secrets, err := scanSettingsFile(file)
+		if err != nil {
+			printError(err.Error())
+			return 1
+		}
+		rel, _ := filepath.Rel(projectPath, file)
+		managed := ddevManaged(file)
+		for _, s := range secrets {
+			total++
+			msg := fmt.Sprintf("%s:%d %s = '%s' -> getenv('%s')", rel, s.line, s.key, maskSecret(s.value), s.envName)
+			if managed {
+				printStatus(msg + " (generated by DDEV with local-only defaults; left as is)")
+				continue
+			}
+			printWarning(msg)
+			fixable = append(fixable, s)
+		}
+	}
+
+	if total == 0 {
+		printSuccess("✓ No hardcoded credentials found in settings files")
+		return 0
+	}
+	if len(fixable) == 0 {
+		return 0
+	}
+	if !*fix {
+		fmt.Println()
+		fmt.Println("Re-run with --fix to move these values into .env and read them with getenv().")
+		return 1
+	}
+
+	values := map[string]string{}
+	var keys []string
+	byFile := map[string][]hardcodedSecret{}
+	for _, s := range fixable {
+		if value, ok := values[s.envName]; !ok {
+			keys = append(keys, s.envName)
+		} else if value != s.value {
+			printError(fmt.Sprintf("%s is used for two different values; rename one of the settings and re-run", s.envName))
+			return 1
+		}
+		values[s.envName] = s.value
+		byFile[s.file] = append(byFile[s.file], s)
+	}
+
+	envPath, examplePath := dotEnvPaths(projectPath)
+	existing, err := readEnvFile(envPath)
+	if err != nil && !os.IsNotExist(err) {
+		printError(fmt.Sprintf("Failed to read %s: %v", envPath, err))
+		return 1
+	}
+	for _, key := range keys {
+		if value, ok := existing[key]; ok && value != values[key] {
+			printError(fmt.Sprintf("%s in .env already holds a different value; reconcile it by hand and re-run", key))
+			return 1
+		}
+	}
+	if err := appendDotEnv(envPath, values, keys); err != nil {
+		printError(fmt.Sprintf("Failed to write %s: %v", envPath, err))
+		return 1
+	}
+	if err := writeDotEnvExample(examplePath, keys, nil); err != nil {
+		printError(fmt.Sprintf("Failed to write %s: %v", examplePath, err))
+		return 1
+	}
+	for file, secrets := range byFile {
+		if err := rewriteSecrets(file, secrets); err != nil {
+			printError(fmt.Sprintf("Failed to rewrite %s: %v", file, err))
+			return 1
+		}
+	}
+	if err := installDotEnvLoader(projectPath); err != nil {
+		return 1
+	}
+	if err := ensureGitignored(projectPath, ".env"); err != nil {
+		printWarning(fmt.Sprintf("Could not add .env to .gitignore: %v", err))
+	}
+
+	printSuccess(fmt.Sprintf("✓ Moved %d values into .env", len(keys)))
+	fmt.Println("settings.php loads .env; set the same environment variables on every other environment.")
+	return 0
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRewriteSecrets(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			"assignment key equals value",
+			"<?php\n$settings['password'] = 'password';\n",
+			"<?php\n$settings['password'] = getenv('PASSWORD');\n",
+		},
+		{
+			"array key equals value",
+			"<?php\n$databases['default']['default'] = [\n  'password' => 'password',\n];\n",
+			"<?php\n$databases['default']['default'] = [\n  'password' => getenv('DATABASES_DEFAULT_DEFAULT_PASSWORD'),\n];\n",
+		},
+		{
+			"earlier pair with the same value",
+			"<?php\n$databases['default']['default'] = [\n  'username' => 'db', 'password' => 'db',\n];\n",
+			"<?php\n$databases['default']['default'] = [\n  'username' => 'db', 'password' => getenv('DATABASES_DEFAULT_DEFAULT_PASSWORD'),\n];\n",
+		},
+		{
+			"two secrets on one line",
+			"<?php\n$config['smtp.settings'] = [\n  'smtp_password' => 'pw', 'api_key' => 'pw',\n];\n",
+			"<?php\n$config['smtp.settings'] = [\n  'smtp_password' => getenv('CONFIG_SMTP_SETTINGS_SMTP_PASSWORD'), 'api_key' => getenv('CONFIG_SMTP_SETTINGS_API_KEY'),\n];\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "settings.php")
+			if err := os.WriteFile(path, []byte(tt.src), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			secrets, err := scanSettingsFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := rewriteSecrets(path, secrets); err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
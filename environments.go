@@ -0,0 +1,216 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type environmentDef struct {
+	URL   string `yaml:"url"`
+	Alias string `yaml:"alias"`
+	SSH   struct {
+		Host string `yaml:"host"`
+		User string `yaml:"user"`
+		Port int    `yaml:"port"`
+		Root string `yaml:"root"`
+	} `yaml:"ssh"`
+	Database environmentDatabase `yaml:"database"`
+}
+
+const currentEnvFile = ".drupal-scripts-env"
+
+const environmentAliasFile = "env.site.yml"
+
+func projectEnvironments(projectPath string) (map[string]environmentDef, error) {
+	if err := loadProjectConfigFrom(projectPath); err != nil {
+		return nil, err
+	}
+	return project.Environments, nil
+}
+
+func environmentNames(envs map[string]environmentDef) []string {
+	names := make([]string, 0, len(envs))
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (e environmentDef) alias(name string) string {
+	if e.Alias != "" {
+		return e.Alias
+	}
+	if e.SSH.Host != "" {
+		return "@env." + name
+	}
+	return ""
+}
+
+func currentEnvironment(projectPath string) string {
+	data, err := os.ReadFile(filepath.Join(projectPath, currentEnvFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+func resolveEnvironment(projectPath, value string) (string, error) {
+	if strings.HasPrefix(value, "@") {
+		return value, nil
+	}
+	envs, err := projectEnvironments(projectPath)
+	if err != nil {
+		return "", err
+	}
+	name := value
+	if name == "" {
+		name = currentEnvironment(projectPath)
+		if name == "" {
+			return "", fmt.Errorf("no environment given and none selected; pass a name or run 'install-drupal env use <name>'")
+		}
+	}
+	env, ok := envs[name]
+	if !ok {
+		return "", fmt.Errorf("unknown environment %q (defined: %s)", name, firstNonEmpty(strings.Join(environmentNames(envs), ", "), "none"))
+	}
+	if value == "" {
+		printStatus(fmt.Sprintf("Using environment %s", name))
+	}
+	if env.Alias == "" && env.SSH.Host != "" {
+		if err := writeEnvironmentAliases(projectPath, envs); err != nil {
+			return "", fmt.Errorf("could not write drush aliases: %v", err)
+		}
+	}
+	return firstNonEmpty(env.alias(name), "@self"), nil
+}
+
+func envFlagAlias(projectPath, value string) (string, error) {
+	switch value {
+	case "":
+		return "", nil
+	case "selected":
+		return resolveEnvironment(projectPath, "")
+	}
+	return resolveEnvironment(projectPath, value)
+}
+
+func writeEnvironmentAliases(projectPath string, envs map[string]environmentDef) error {
+	var b strings.Builder
+	b.WriteString("# Managed by drupal-scripts from the environments in drupal-scripts.yml.\n")
+	count := 0
+	for _, name := range environmentNames(envs) {
+		env := envs[name]
+		if env.Alias != "" || env.SSH.Host == "" {
+			continue
+		}
+		fmt.Fprintf(&b, "%s:\n  host: %s\n", name, yamlQuote(env.SSH.Host))
+		if env.SSH.User != "" {
+			fmt.Fprintf(&b, "  user: %s\n", yamlQuote(env.SSH.User))
+		}
+		if env.SSH.Root != "" {
+			fmt.Fprintf(&b, "  root: %s\n", yamlQuote(env.SSH.Root))
+		}
+		if env.URL != "" {
+			fmt.Fprintf(&b, "  uri: %s\n", yamlQuote(env.URL))
+		}
+		if env.SSH.Port != 0 && env.SSH.Port != 22 {
+			fmt.Fprintf(&b, "  ssh:\n    options: '-p %d'\n", env.SSH.Port)
+		}
+		count++
+	}
+	path := filepath.Join(projectPath, "drush", "sites", environmentAliasFile)
+	if count == 0 {
+		return removeFile(path)
+	}
+	return writeFile(path, []byte(b.String()))
+}
+
+func runEnvList(args []string) int {
+	fs := flag.NewFlagSet("env list", flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the project")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	envs, err := projectEnvironments(projectPath)
+	if err != nil {
+		return 1
+	}
+	if len(envs) == 0 {
+		printWarning("No environments defined; add an environments section to drupal-scripts.yml")
+		return 0
+	}
+	current := currentEnvironment(projectPath)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "\tNAME\tURL\tDRUSH ALIAS")
+	for _, name := range environmentNames(envs) {
+		marker := ""
+		if name == current {
+			marker = "*"
+		}
+		env := envs[name]
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", marker, name, firstNonEmpty(env.URL, "-"), firstNonEmpty(env.alias(name), "@self"))
+	}
+	w.Flush()
+	return 0
+}
+
+func runEnvUse(args []string) int {
+	fs := flag.NewFlagSet("env use", flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the project")
+	clear := fs.Bool("clear", false, "Forget the selected environment")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if *clear {
+		if err := os.Remove(filepath.Join(projectPath, currentEnvFile)); err != nil && !os.IsNotExist(err) {
+			printError(err.Error())
+			return 1
+		}
+		printSuccess("✓ No environment selected")
+		return 0
+	}
+	if fs.NArg() != 1 {
+		printError("Usage: install-drupal env use [--path <dir>] <name>")
+		return 2
+	}
+	name := fs.Arg(0)
+	envs, err := projectEnvironments(projectPath)
+	if err != nil {
+		return 1
+	}
+	env, ok := envs[name]
+	if !ok {
+		printError(fmt.Sprintf("Unknown environment %q (defined: %s)", name, firstNonEmpty(strings.Join(environmentNames(envs), ", "), "none")))
+		return 1
+	}
+	if err := writeEnvironmentAliases(projectPath, envs); err != nil {
+		printError(fmt.Sprintf("Failed to write drush aliases: %v", err))
+		return 1
+	}
+	if err := writeFile(filepath.Join(projectPath, currentEnvFile), []byte(name+"\n")); err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if err := ensureGitignored(projectPath, currentEnvFile); err != nil {
+		printWarning(fmt.Sprintf("Could not add %s to .gitignore: %v", currentEnvFile, err))
+	}
+	printSuccess(fmt.Sprintf("✓ Using environment %s (%s)", name, firstNonEmpty(env.alias(name), "@self")))
+	fmt.Println("Commands that take --env, --from or --to now default to it.")
+	return 0
+}
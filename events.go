@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+type event struct {
+	Time     string  `json:"time"`
+	Event    string  `json:"event"`
+	Level    string  `json:"level,omitempty"`
+	Message  string  `json:"message,omitempty"`
+	Step     string  `json:"step,omitempty"`
+	Title    string  `json:"title,omitempty"`
+	Index    int     `json:"index,omitempty"`
+	Total    int     `json:"total,omitempty"`
+	Duration float64 `json:"duration_seconds,omitempty"`
+	URL      string  `json:"url,omitempty"`
+	Error    string  `json:"error,omitempty"`
+	Status   string  `json:"status,omitempty"`
+}
+
+var eventOutput *os.File
+
+func jsonOutput() bool {
+	return eventOutput != nil
+}
+
+func startJSONOutput() {
+	if eventOutput != nil {
+		return
+	}
+	eventOutput = os.Stdout
+	os.Stdout = os.Stderr
+	useColor = false
+}
+
+func emitEvent(e event) {
+	if eventOutput == nil {
+		return
+	}
+	e.Time = time.Now().Format(time.RFC3339)
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(eventOutput, string(data))
+}
+
+func emitStepEvent(kind string, index, total int, step pipelineStep, elapsed time.Duration, err error) {
+	e := event{Event: kind, Step: step.name, Title: step.title, Index: index + 1, Total: total, Duration: elapsed.Seconds()}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	emitEvent(e)
+}
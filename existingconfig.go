@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var existingConfig bool
+
+func hasExistingConfig(projectPath string) bool {
+	_, err := os.Stat(filepath.Join(projectPath, "config", "sync", "system.site.yml"))
+	return err == nil
+}
+
+func copyExistingConfig(src, dest string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(src, "system.site.yml")); err != nil {
+		return fmt.Errorf("%s is not a full config export (no system.site.yml)", src)
+	}
+	copied := 0
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yml") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(src, entry.Name()))
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(dest, entry.Name()), data); err != nil {
+			return err
+		}
+		copied++
+	}
+	printStatus(fmt.Sprintf("Copied %d config files from %s", copied, src))
+	return nil
+}
+
+func prepareExistingConfig(projectPath, configSyncPath string) error {
+	if opts.existingConfig != "" {
+		if err := copyExistingConfig(opts.existingConfig, configSyncPath); err != nil {
+			printError(fmt.Sprintf("Failed to copy existing config: %v", err))
+			return err
+		}
+	}
+	existingConfig = hasExistingConfig(projectPath)
+	if existingConfig {
+		printStatus("config/sync holds a full export; installing from it with --existing-config")
+	}
+	return nil
+}
+
+func skipForExistingConfig(what string) bool {
+	if existingConfig {
+		printStatus(fmt.Sprintf("Skipping %s: the existing configuration already defines it", what))
+	}
+	return existingConfig
+}
+
+type exportedSite struct {
+	UUID    string
+	Profile string
+}
+
+func readExportedSite(projectPath string) (exportedSite, error) {
+	syncPath := filepath.Join(projectPath, "config", "sync")
+	var site struct {
+		UUID string `yaml:"uuid"`
+	}
+	var extension struct {
+		Profile string `yaml:"profile"`
+	}
+	data, err := os.ReadFile(filepath.Join(syncPath, "system.site.yml"))
+	if err != nil {
+		return exportedSite{}, err
+	}
+	if err := yamlUnmarshal(data, &site); err != nil {
+		return exportedSite{}, fmt.Errorf("system.site.yml: %v", err)
+	}
+	if data, err := os.ReadFile(filepath.Join(syncPath, "core.extension.yml")); err == nil {
+		if err := yamlUnmarshal(data, &extension); err != nil {
+			return exportedSite{}, fmt.Errorf("core.extension.yml: %v", err)
+		}
+	}
+	return exportedSite{UUID: site.UUID, Profile: extension.Profile}, nil
+}
+
+const deleteShortcutsPHP = `$m = \Drupal::entityTypeManager();
+foreach (['shortcut', 'shortcut_set'] as $type) {
+  if ($m->hasDefinition($type)) {
+    $storage = $m->getStorage($type);
+    $storage->delete($storage->loadMultiple());
+  }
+}`
+
+func reconcileSiteUUID(projectPath string) error {
+	site, err := readExportedSite(projectPath)
+	if err != nil {
+		return err
+	}
+	if site.UUID == "" {
+		return fmt.Errorf("system.site.yml has no uuid")
+	}
+	printStatus(fmt.Sprintf("Setting the site UUID to %s from config/sync", site.UUID))
+	if err := runDDEVQuiet(projectPath, "drush", "config:set", "system.site", "uuid", site.UUID, "--yes"); err != nil {
+		return fmt.Errorf("drush config:set system.site uuid failed: %v", err)
+	}
+	printStatus("Deleting shortcut entities created by the install profile")
+	if err := runDDEVQuiet(projectPath, "drush", "php:eval", deleteShortcutsPHP); err != nil {
+		return fmt.Errorf("deleting shortcut entities failed: %v", err)
+	}
+	return nil
+}
+
+func installExportedSite(projectPath string) error {
+	site, err := readExportedSite(projectPath)
+	if err != nil {
+		return err
+	}
+	profile := firstNonEmpty(site.Profile, siteProfile())
+	printStatus(fmt.Sprintf("Installing the %s profile, then importing config/sync over it", profile))
+	if err := runDDEV(projectPath, "drush", "site:install", profile, "--yes",
+		"--account-name=admin", "--account-pass="+opts.adminPassword); err != nil {
+		return err
+	}
+	if err := reconcileSiteUUID(projectPath); err != nil {
+		return err
+	}
+	if _, err := validateConfigImport(projectPath, "", false); err != nil {
+		return err
+	}
+	return runDDEV(projectPath, "drush", "config:import", "--yes")
+}
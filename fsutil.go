@@ -0,0 +1,116 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	dirPerm    os.FileMode = 0755
+	execPerm   os.FileMode = 0755
+	filePerm   os.FileMode = 0644
+	secretPerm os.FileMode = 0600
+	ownerWrite os.FileMode = 0200
+)
+
+func ensureDir(path string) error {
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return nil
+	}
+	if dryRunFile("create directory", path) {
+		return nil
+	}
+	return os.MkdirAll(path, dirPerm)
+}
+
+func writeFile(path string, data []byte) error {
+	if dryRunFile("write", path) {
+		return nil
+	}
+	if err := ensureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+
+	perm := filePerm
+	if info, err := os.Stat(path); err == nil {
+		perm = info.Mode().Perm()
+		if perm&ownerWrite == 0 {
+			_ = os.Chmod(path, perm|ownerWrite)
+			defer os.Chmod(path, perm)
+		}
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+func writeSecretFile(path string, data []byte) error {
+	if dryRunFile("write (mode 0600)", path) {
+		return nil
+	}
+	if err := ensureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, secretPerm); err != nil {
+		return err
+	}
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	if err := os.Chmod(path, secretPerm); err != nil && !onWindowsMount(path) {
+		return err
+	}
+	return nil
+}
+
+func writeExecutable(path string, data []byte) error {
+	if err := writeFile(path, data); err != nil {
+		return err
+	}
+	if opts.dryRun || runtime.GOOS == "windows" {
+		return nil
+	}
+	if err := os.Chmod(path, execPerm); err != nil && !onWindowsMount(path) {
+		return err
+	}
+	return nil
+}
+
+func removeFile(path string) error {
+	if _, err := os.Lstat(path); os.IsNotExist(err) {
+		return nil
+	}
+	if dryRunFile("remove", path) {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func phpPath(path string) string {
+	return filepath.ToSlash(path)
+}
+
+func isWSL() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	data, err := os.ReadFile("/proc/version")
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(data)), "microsoft")
+}
+
+func onWindowsMount(path string) bool {
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	if !isWSL() {
+		return false
+	}
+	clean := filepath.ToSlash(filepath.Clean(path))
+	return strings.HasPrefix(clean, "/mnt/") && len(clean) > 6
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const privacySettingsFile = "settings.privacy.php"
+
+const privacySettings = `<?php
+
+/**
+ * @file
+ * Data retention settings, generated by the drupal-scripts gdpr preset.
+ *
+ * Review every value with whoever is responsible for data protection on this
+ * project and record the agreed retention periods here.
+ */
+
+// Number of watchdog (dblog) entries to keep; older entries are pruned by cron.
+$config['dblog.settings']['row_limit'] = 10000;
+
+// Delete an account's content when the account is cancelled.
+// $config['user.settings']['cancel_method'] = 'user_cancel_delete';
+
+// Purge webform submissions after a number of days (per form, in the form's
+// settings) - e.g. for a contact form:
+// $config['webform.webform.contact']['settings']['purge'] = 'all';
+// $config['webform.webform.contact']['settings']['purge_days'] = 90;
+
+// Seconds before idle sessions are garbage collected.
+// ini_set('session.gc_maxlifetime', 86400);
+`
+
+const privacySettingsInclude = "if (file_exists(__DIR__ . '/" + privacySettingsFile + "')) {\n  include __DIR__ . '/" + privacySettingsFile + "';\n}"
+
+func init() {
+	registerPreset(&preset{
+		name:        "gdpr",
+		description: "EU cookie consent banner (eu_cookie_compliance) and a data-retention settings stub",
+		packages:    []string{"drupal/eu_cookie_compliance"},
+		modules:     []string{"eu_cookie_compliance"},
+		settings: []configSetting{
+			{name: "eu_cookie_compliance.settings", values: [][2]string{
+				{"method", "opt_in"},
+				{"popup_enabled", "true"},
+				{"popup_info.value", "<h2>We use cookies on this site</h2><p>We only set non-essential cookies if you accept them.</p>"},
+				{"popup_agree_button_message", "Accept"},
+				{"show_disagree_button", "true"},
+				{"disagree_button_label", "Decline"},
+				{"popup_link", "/privacy"},
+				{"withdraw_enabled", "true"},
+			}},
+		},
+		ignoreConfig: []string{"eu_cookie_compliance.settings:popup_info", "eu_cookie_compliance.settings:popup_link"},
+		tour: []tourStop{
+			{path: "/admin/config/system/eu-cookie-compliance", label: "Cookie consent banner settings"},
+		},
+		apply: applyPrivacySettings,
+	})
+}
+
+func applyPrivacySettings(projectPath string) error {
+	path := filepath.Join(siteDefaultDir(projectPath), privacySettingsFile)
+	if _, err := os.Stat(path); err == nil {
+		printWarning(fmt.Sprintf("%s already exists; leaving it unchanged", privacySettingsFile))
+	} else if err := writeFile(path, []byte(privacySettings)); err != nil {
+		printError("Failed to write data retention settings")
+		return err
+	}
+	if err := appendToSettings(projectPath, "Data retention settings (drupal-scripts gdpr preset).", privacySettingsInclude); err != nil {
+		return err
+	}
+	printSuccess("✓ Data retention settings stub written to " + siteDefaultFile(projectPath, privacySettingsFile))
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+)
+
+func runScaffoldGitpod(args []string) int {
+	fs := flag.NewFlagSet("scaffold gitpod", flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the project")
+	force := fs.Bool("force", false, "Overwrite an existing .gitpod.yml")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	content, err := renderScaffold("gitpod.yml", map[string]string{"Name": filepath.Base(projectPath)})
+	if err != nil {
+		printError(fmt.Sprintf("Failed to render gitpod.yml: %v", err))
+		return 1
+	}
+	if err := writeScaffoldFile(filepath.Join(projectPath, ".gitpod.yml"), content, *force); err != nil {
+		return 1
+	}
+	fmt.Println()
+	fmt.Println("Open the repository in Gitpod (https://gitpod.io/#<repository URL>). A prebuild pulls the DDEV")
+	fmt.Println("images and runs composer install; each workspace starts DDEV and previews the site on port 8080.")
+	return 0
+}
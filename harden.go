@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"unicode"
+)
+
+const minAdminPasswordLength = 12
+
+const hardenServicesFile = "harden.services.yml"
+
+const hardenServices = `# Managed by drupal-scripts --harden.
+parameters:
+  session.storage.options:
+    gc_probability: 1
+    gc_divisor: 100
+    gc_maxlifetime: 200000
+    cookie_lifetime: 0
+    cookie_secure: true
+    cookie_httponly: true
+    cookie_samesite: Lax
+    sid_length: 48
+    sid_bits_per_character: 6
+`
+
+const hardenSettingsInclude = "$settings['container_yamls'][] = __DIR__ . '/" + hardenServicesFile + "';"
+
+var hardenSettings = []configSetting{
+	{name: "user.settings", values: [][2]string{
+		{"register", "admin_only"},
+	}},
+	{name: "seckit.settings", values: [][2]string{
+		{"seckit_clickjacking.x_frame", "1"},
+		{"seckit_csrf.origin", "true"},
+		{"seckit_various.referrer_policy", "true"},
+		{"seckit_various.referrer_policy_policy", "strict-origin-when-cross-origin"},
+	}},
+	{name: "login_security.settings", values: [][2]string{
+		{"track_time", "1"},
+		{"user_wrong_count", "5"},
+		{"host_wrong_count", "10"},
+	}},
+}
+
+func init() {
+	registerPreset(&preset{
+		name:        "harden",
+		description: "security baseline: admin-only registration, strong admin password, seckit, login_security, secure session cookies",
+		packages:    []string{"drupal/seckit", "drupal/login_security"},
+		modules:     []string{"seckit", "login_security"},
+		settings:    hardenSettings,
+		composerScripts: []composerScript{
+			{name: "post-install-cmd", command: "composer audit --locked --no-dev || true"},
+		},
+		scaffoldExcludes: []string{"INSTALL.txt", "README.md", "example.gitignore", "web.config"},
+		tour: []tourStop{
+			{path: "/admin/config/system/seckit", label: "Security Kit settings"},
+			{path: "/admin/config/people/login_security", label: "Login Security settings"},
+		},
+		setup: requireStrongAdminPassword,
+		apply: applyHardening,
+	})
+}
+
+func strongPassword(password string) bool {
+	if len(password) < minAdminPasswordLength {
+		return false
+	}
+	var lower, upper, digit, other bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+	classes := 0
+	for _, ok := range []bool{lower, upper, digit, other} {
+		if ok {
+			classes++
+		}
+	}
+	return classes >= 3
+}
+
+func requireStrongAdminPassword() error {
+	if strongPassword(opts.adminPassword) {
+		return nil
+	}
+	if opts.adminPassword != "admin" {
+		return fmt.Errorf("--admin-password must be at least %d characters and mix upper case, lower case, digits or symbols", minAdminPasswordLength)
+	}
+	for {
+		password, err := randomString(apr1Alphabet[2:], 20)
+		if err != nil {
+			return err
+		}
+		if strongPassword(password) {
+			opts.adminPassword = password
+			break
+		}
+	}
+	printStatus("Generated a strong admin password (shown at the end of the install)")
+	return nil
+}
+
+func applyHardening(projectPath string) error {
+	siteDir := siteDefaultDir(projectPath)
+	if err := writeFile(filepath.Join(siteDir, hardenServicesFile), []byte(hardenServices)); err != nil {
+		printError("Failed to write session cookie settings")
+		return err
+	}
+
+	if err := appendToSettings(projectPath, "Secure session cookie flags (drupal-scripts --harden).", hardenSettingsInclude); err != nil {
+		return err
+	}
+
+	if err := runDDEVQuiet(projectPath, "drush", "cache:rebuild"); err != nil {
+		printWarning("Failed to rebuild caches after hardening")
+	}
+	printSuccess("✓ Security baseline applied")
+	return nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+//go:embed ide
+var embeddedIDETemplates embed.FS
+
+var ddevPHPVersionPattern = regexp.MustCompile(`(?m)^php_version:\s*["']?([0-9.]+)`)
+
+const defaultPHPVersion = "8.3"
+
+func projectPHPVersion(projectPath string) string {
+	if config, err := os.ReadFile(filepath.Join(projectPath, ".ddev", "config.yaml")); err == nil {
+		if m := ddevPHPVersionPattern.FindSubmatch(config); m != nil {
+			return string(m[1])
+		}
+	}
+	return defaultPHPVersion
+}
+
+type ideFile struct {
+	template string
+	target   string
+}
+
+var projectEditorFiles = []ideFile{
+	{template: "editorconfig.tmpl", target: ".editorconfig"},
+	{template: "gitattributes.tmpl", target: ".gitattributes"},
+}
+
+var ideFiles = map[string][]ideFile{
+	"vscode": {
+		{template: "vscode/settings.json.tmpl", target: ".vscode/settings.json"},
+		{template: "vscode/extensions.json.tmpl", target: ".vscode/extensions.json"},
+		{template: "vscode/launch.json.tmpl", target: ".vscode/launch.json"},
+	},
+	"phpstorm": {
+		{template: "phpstorm/php.xml.tmpl", target: ".idea/php.xml"},
+		{template: "phpstorm/externalDependencies.xml.tmpl", target: ".idea/externalDependencies.xml"},
+		{template: "phpstorm/codeStyles/codeStyleConfig.xml.tmpl", target: ".idea/codeStyles/codeStyleConfig.xml"},
+		{template: "phpstorm/codeStyles/Project.xml.tmpl", target: ".idea/codeStyles/Project.xml"},
+	},
+}
+
+var defaultIDEs = []string{"vscode", "phpstorm"}
+
+type ideTemplateData struct {
+	Docroot    string
+	PHPVersion string
+}
+
+func selectedIDEs() ([]string, error) {
+	if len(opts.ide) == 0 {
+		return defaultIDEs, nil
+	}
+	var ides []string
+	for _, ide := range opts.ide {
+		ide = strings.ToLower(ide)
+		if ide == "none" {
+			return nil, nil
+		}
+		if _, ok := ideFiles[ide]; !ok {
+			return nil, fmt.Errorf("invalid --ide %q (expected vscode, phpstorm or none)", ide)
+		}
+		if !containsString(ides, ide) {
+			ides = append(ides, ide)
+		}
+	}
+	return ides, nil
+}
+
+func renderIDEFile(name string, data ideTemplateData) ([]byte, error) {
+	body, err := embeddedIDETemplates.ReadFile("ide/" + name)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(name).Funcs(scaffoldFuncs).Option("missingkey=error").Parse(string(body))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func scaffoldedCopy(projectPath, docroot, path, name string) bool {
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return os.IsNotExist(err)
+	}
+	original, err := os.ReadFile(filepath.Join(projectPath, docroot, "core", "assets", "scaffold", "files", name))
+	return err == nil && bytes.Equal(current, original)
+}
+
+func writeEditorSettings(projectPath string) error {
+	ides, err := selectedIDEs()
+	if err != nil {
+		return err
+	}
+	data := ideTemplateData{Docroot: projectDocroot(projectPath), PHPVersion: projectPHPVersion(projectPath)}
+
+	var written, kept []string
+	var unscaffold []string
+	if lockSnapshot != "" {
+		printStatus("Leaving .editorconfig and .gitattributes to drupal-scaffold (lock snapshot)")
+	} else {
+		for _, f := range projectEditorFiles {
+			target := filepath.Join(projectPath, f.target)
+			if !scaffoldedCopy(projectPath, data.Docroot, target, strings.TrimPrefix(f.target, ".")) {
+				kept = append(kept, f.target)
+				continue
+			}
+			content, err := renderIDEFile(f.template, data)
+			if err != nil {
+				return err
+			}
+			if err := writeFile(target, content); err != nil {
+				return err
+			}
+			written = append(written, f.target)
+			unscaffold = append(unscaffold, f.target)
+		}
+	}
+	for _, ide := range ides {
+		for _, f := range ideFiles[ide] {
+			target := filepath.Join(projectPath, filepath.FromSlash(f.target))
+			if _, err := os.Stat(target); err == nil {
+				kept = append(kept, f.target)
+				continue
+			}
+			content, err := renderIDEFile(f.template, data)
+			if err != nil {
+				return err
+			}
+			if err := writeFile(target, content); err != nil {
+				return err
+			}
+			written = append(written, f.target)
+		}
+	}
+	if containsString(ides, "phpstorm") {
+		if err := ensureGitignored(projectPath, ".idea/workspace.xml"); err != nil {
+			printWarning(fmt.Sprintf("Could not add .idea/workspace.xml to .gitignore: %v", err))
+		}
+	}
+
+	if len(unscaffold) > 0 {
+		composerPath := filepath.Join(projectPath, "composer.json")
+		composer, err := readJSONFile(composerPath)
+		if err != nil {
+			return err
+		}
+		mapping := composer.object("extra").object("drupal-scaffold").object("file-mapping")
+		for _, file := range unscaffold {
+			if _, ok := mapping.values["[project-root]/"+file]; !ok {
+				mapping.set("[project-root]/"+file, false)
+			}
+		}
+		if err := writeJSONFile(composerPath, composer); err != nil {
+			return err
+		}
+	}
+
+	if len(kept) > 0 {
+		printStatus(fmt.Sprintf("Keeping existing %s", strings.Join(kept, ", ")))
+	}
+	printSuccess(fmt.Sprintf("✓ Wrote %d editor and IDE settings files (PHP %s)", len(written), data.PHPVersion))
+	return nil
+}
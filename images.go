@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+var imageBinaries = [][2]string{
+	{"jpegoptim", "jpegoptim"},
+	{"pngquant", "pngquant"},
+	{"webp", "cwebp"},
+}
+
+func init() {
+	registerPreset(&preset{
+		name:        "images",
+		description: "jpegoptim, pngquant and WebP in the web container, with an Image Optimize pipeline that runs them on every image style derivative",
+		packages:    []string{"drupal/imageapi_optimize", "drupal/imageapi_optimize_binaries", "drupal/imageapi_optimize_webp"},
+		modules:     []string{"imageapi_optimize", "imageapi_optimize_binaries", "imageapi_optimize_webp"},
+		settings: []configSetting{
+			{name: "imageapi_optimize.settings", values: [][2]string{
+				{"default_pipeline", "local_binaries"},
+			}},
+		},
+		tour: []tourStop{
+			{path: "/admin/config/media/imageapi-optimize-pipelines", label: "Image Optimize pipelines"},
+		},
+		setup: addImageBinaries,
+		apply: checkImageBinaries,
+	})
+}
+
+func addImageBinaries() error {
+	for _, pkg := range imageBinaries {
+		if !containsString(project.DDEV.WebimageExtraPackages, pkg[0]) {
+			project.DDEV.WebimageExtraPackages = append(project.DDEV.WebimageExtraPackages, pkg[0])
+		}
+	}
+	return nil
+}
+
+func checkImageBinaries(projectPath string) error {
+	var missing []string
+	for _, pkg := range imageBinaries {
+		if err := runDDEVQuiet(projectPath, "exec", "which", pkg[1]); err != nil {
+			missing = append(missing, pkg[1])
+		}
+	}
+	if len(missing) > 0 {
+		printWarning(fmt.Sprintf("%s not found in the web container; run 'ddev restart' to rebuild the image", strings.Join(missing, ", ")))
+	}
+	return nil
+}
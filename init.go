@@ -0,0 +1,169 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type userConfigValue struct {
+	path  []string
+	value string
+}
+
+func yamlQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func lineIndent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
+
+func setUserConfigValue(lines []string, v userConfigValue) []string {
+	key := v.path[len(v.path)-1]
+	if len(v.path) == 1 {
+		for i, line := range lines {
+			if lineIndent(line) == 0 && strings.HasPrefix(line, key+":") {
+				lines[i] = key + ": " + v.value
+				return lines
+			}
+		}
+		return append(lines, key+": "+v.value)
+	}
+
+	section := v.path[0]
+	for i, line := range lines {
+		if lineIndent(line) != 0 || strings.TrimSpace(stripYAMLComment(line)) != section+":" {
+			continue
+		}
+		indent := ""
+		for j := i + 1; j < len(lines); j++ {
+			trimmed := strings.TrimSpace(lines[j])
+			if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+				continue
+			}
+			if lineIndent(lines[j]) == 0 {
+				break
+			}
+			if indent == "" {
+				indent = strings.Repeat(" ", lineIndent(lines[j]))
+			}
+			if strings.HasPrefix(lines[j], indent+key+":") {
+				lines[j] = indent + key + ": " + v.value
+				return lines
+			}
+		}
+		entry := firstNonEmpty(indent, "  ") + key + ": " + v.value
+		return append(lines[:i+1], append([]string{entry}, lines[i+1:]...)...)
+	}
+	return append(lines, section+":", "  "+key+": "+v.value)
+}
+
+func writeUserConfigValues(values []userConfigValue) (string, error) {
+	dir, err := userConfigDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "config.yml")
+	var lines []string
+	if data, err := os.ReadFile(path); err == nil {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	} else if !os.IsNotExist(err) {
+		return "", err
+	}
+	for _, v := range values {
+		lines = setUserConfigValue(lines, v)
+	}
+	if err := ensureDir(dir); err != nil {
+		return "", err
+	}
+	return path, writeFile(path, []byte(strings.Join(lines, "\n")+"\n"))
+}
+
+func applyDDEVTelemetry(optIn *bool) {
+	if optIn == nil || !commandExists("ddev") {
+		return
+	}
+	if output, err := runCommandOutput("ddev", "config", "global", "--instrumentation-opt-in="+strconv.FormatBool(*optIn)); err != nil {
+		fmt.Print(output)
+		printWarning("Failed to set DDEV's usage data preference")
+	}
+}
+
+func promptDefault(question, current string) string {
+	if current != "" {
+		question = fmt.Sprintf("%s [%s]", question, current)
+	}
+	if answer := promptLine(question + ": "); answer != "" {
+		return answer
+	}
+	return current
+}
+
+func runInit(args []string) int {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	opts.interactive = stdinIsTerminal()
+	if !opts.interactive {
+		printError("init asks questions and needs a terminal; edit ~/.drupal-scripts/config.yml directly instead")
+		return 2
+	}
+	settings, err := loadUserSettings()
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	printStatus("Setting up your defaults; press Enter to keep the value in brackets")
+	fmt.Println()
+
+	rec := recommendProvider(detectHost())
+	provider := ""
+	for provider != "docker" && provider != "colima" {
+		provider = strings.ToLower(promptDefault("Docker provider (docker or colima)", firstNonEmpty(settings.Defaults.DockerProvider, rec.provider)))
+	}
+
+	workspace := promptDefault("Directory to create projects in ('none' for the current directory)", firstNonEmpty(settings.Workspace, "~/Sites"))
+	if strings.EqualFold(workspace, "none") {
+		workspace = ""
+	}
+
+	policyURL := promptDefault("Organization policy URL or file ('none' for no policy)", firstNonEmpty(settings.PolicyURL, "none"))
+	if strings.EqualFold(policyURL, "none") {
+		policyURL = ""
+	}
+	if policyURL != "" {
+		if _, err := fetchOrgPolicy(policyURL); err != nil {
+			printWarning("The policy could not be loaded now; it is saved anyway and checked on every install")
+		}
+	}
+
+	current := "n"
+	if settings.Telemetry != nil && *settings.Telemetry {
+		current = "y"
+	}
+	telemetry := strings.ToLower(promptDefault("Share anonymous usage data with DDEV? (y/n)", current)) == "y"
+
+	values := []userConfigValue{
+		{path: []string{"defaults", "docker_provider"}, value: provider},
+		{path: []string{"workspace"}, value: yamlQuote(workspace)},
+		{path: []string{"policy_url"}, value: yamlQuote(policyURL)},
+		{path: []string{"telemetry"}, value: strconv.FormatBool(telemetry)},
+	}
+	path, err := writeUserConfigValues(values)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to write the user config: %v", err))
+		return 1
+	}
+	applyDDEVTelemetry(&telemetry)
+
+	fmt.Println()
+	printSuccess(fmt.Sprintf("✓ Saved %s", path))
+	fmt.Println("Installs now use these answers; flags still override them. Re-run 'install-drupal init' to change them.")
+	return 0
+}
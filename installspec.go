@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+type installSettings struct {
+	ProjectName    string   `yaml:"project_name"`
+	DrupalVersion  string   `yaml:"drupal_version"`
+	SiteName       string   `yaml:"site_name"`
+	DockerProvider string   `yaml:"docker_provider"`
+	PHPVersion     string   `yaml:"php_version"`
+	Presets        []string `yaml:"presets"`
+	Packages       []string `yaml:"packages"`
+	Modules        []string `yaml:"modules"`
+}
+
+func (s installSettings) empty() bool {
+	return s.ProjectName == "" && s.DrupalVersion == "" && s.SiteName == "" && s.DockerProvider == "" &&
+		s.PHPVersion == "" && len(s.Presets) == 0 && len(s.Packages) == 0 && len(s.Modules) == 0
+}
+
+var drupalVersion = "^11"
+
+var drupalVersionPattern = regexp.MustCompile(`^[\^~]?\d+(\.\d+|\.x)*$`)
+
+func applyInstallSettings() error {
+	spec := project.Install
+	if v := strings.TrimSpace(spec.DrupalVersion); v != "" {
+		if !drupalVersionPattern.MatchString(v) {
+			return fmt.Errorf("invalid install.drupal_version %q (expected a major version or constraint, e.g. 10 or ^10.3)", v)
+		}
+		if !strings.ContainsAny(v, "^~.") {
+			v = "^" + v
+		}
+		drupalVersion = v
+	}
+	if opts.projectName == "" {
+		opts.projectName = spec.ProjectName
+	}
+	if !opts.flagSet["site-name"] && spec.SiteName != "" {
+		opts.siteName = spec.SiteName
+	}
+	if opts.dockerProvider == "" {
+		opts.dockerProvider = strings.ToLower(spec.DockerProvider)
+		if opts.dockerProvider != "" && opts.dockerProvider != "docker" && opts.dockerProvider != "colima" {
+			return fmt.Errorf("invalid install.docker_provider %q (expected docker or colima)", spec.DockerProvider)
+		}
+	}
+	if spec.PHPVersion != "" {
+		if project.DDEV.PHPVersion != "" && project.DDEV.PHPVersion != spec.PHPVersion {
+			return fmt.Errorf("install.php_version %s conflicts with ddev.php_version %s", spec.PHPVersion, project.DDEV.PHPVersion)
+		}
+		project.DDEV.PHPVersion = spec.PHPVersion
+	}
+	for _, name := range spec.Presets {
+		if !containsString(opts.presets, name) {
+			opts.presets = append(opts.presets, name)
+		}
+	}
+	for _, pkg := range spec.Packages {
+		if !containsPackage(composerPackages, pkg) {
+			composerPackages = append(composerPackages, pkg)
+		}
+	}
+	for _, module := range spec.Modules {
+		if !containsString(drupalModules, module) {
+			drupalModules = append(drupalModules, module)
+		}
+	}
+	if !spec.empty() {
+		printStatus(fmt.Sprintf("Install spec: drupal/recommended-project:%s, %d extra modules", drupalVersion, len(spec.Modules)))
+	}
+	return nil
+}
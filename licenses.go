@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type dependency struct {
+	ecosystem string
+	name      string
+	version   string
+	licenses  []string
+	source    string
+	dev       bool
+}
+
+type licenseSettings struct {
+	Allow []string `yaml:"allow"`
+}
+
+var defaultAllowedLicenses = []string{
+	"MIT", "BSD-2-Clause", "BSD-3-Clause", "Apache-2.0", "ISC", "0BSD", "Unlicense",
+	"CC0-1.0", "Zlib", "Python-2.0", "BlueOak-1.0.0", "GPL-2.0-or-later", "GPL-2.0+",
+}
+
+var copyleftLicensePrefixes = []string{"GPL", "LGPL", "AGPL", "MPL", "EPL", "EUPL", "CDDL", "OSL", "CC-BY-SA"}
+
+type npmLock struct {
+	Packages map[string]struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		License any    `json:"license"`
+		Dev     bool   `json:"dev"`
+		Link    bool   `json:"link"`
+	} `json:"packages"`
+}
+
+func composerDependencies(projectPath string) ([]dependency, error) {
+	lock, err := loadComposerLock(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	var deps []dependency
+	for i, list := range [][]lockedPackage{lock.Packages, lock.PackagesDev} {
+		for _, pkg := range list {
+			deps = append(deps, dependency{
+				ecosystem: "composer",
+				name:      pkg.Name,
+				version:   pkg.Version,
+				licenses:  pkg.License,
+				source:    "composer.lock",
+				dev:       i == 1,
+			})
+		}
+	}
+	return deps, nil
+}
+
+func npmLockFiles(projectPath string) []string {
+	var found []string
+	for _, dir := range []string{"themes", "modules", "profiles"} {
+		root := filepath.Join(projectPath, projectDocroot(projectPath), dir, "custom")
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() && d.Name() == "node_modules" {
+				return filepath.SkipDir
+			}
+			if d.Name() == "package-lock.json" {
+				found = append(found, path)
+			}
+			return nil
+		})
+	}
+	sort.Strings(found)
+	return found
+}
+
+func npmDependencies(projectPath, lockPath string) ([]dependency, error) {
+	data, err := os.ReadFile(lockPath)
+	if err != nil {
+		return nil, err
+	}
+	var lock npmLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("%s: %v", lockPath, err)
+	}
+	if lock.Packages == nil {
+		return nil, fmt.Errorf("%s uses lockfileVersion 1; regenerate it with npm 7 or later", lockPath)
+	}
+	rel, _ := filepath.Rel(projectPath, lockPath)
+
+	var deps []dependency
+	for key, pkg := range lock.Packages {
+		if key == "" || pkg.Link {
+			continue
+		}
+		name := pkg.Name
+		if name == "" {
+			name = key[strings.LastIndex(key, "node_modules/")+len("node_modules/"):]
+		}
+		deps = append(deps, dependency{
+			ecosystem: "npm",
+			name:      name,
+			version:   pkg.Version,
+			licenses:  npmLicenses(pkg.License),
+			source:    rel,
+			dev:       pkg.Dev,
+		})
+	}
+	return deps, nil
+}
+
+func npmLicenses(license any) []string {
+	var expr string
+	switch v := license.(type) {
+	case string:
+		expr = v
+	case map[string]any:
+		expr, _ = v["type"].(string)
+	}
+	expr = strings.Trim(strings.TrimSpace(expr), "()")
+	if expr == "" {
+		return nil
+	}
+	var alternatives []string
+	for _, alt := range strings.Split(expr, " OR ") {
+		alternatives = append(alternatives, strings.Trim(strings.TrimSpace(alt), "()"))
+	}
+	return alternatives
+}
+
+func collectDependencies(projectPath string) ([]dependency, error) {
+	deps, err := composerDependencies(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	for _, lockPath := range npmLockFiles(projectPath) {
+		npm, err := npmDependencies(projectPath, lockPath)
+		if err != nil {
+			printWarning(err.Error())
+			continue
+		}
+		deps = append(deps, npm...)
+	}
+
+	seen := map[string]bool{}
+	var unique []dependency
+	for _, dep := range deps {
+		key := dep.ecosystem + ":" + dep.name + "@" + dep.version
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, dep)
+	}
+	sort.Slice(unique, func(i, j int) bool {
+		if unique[i].ecosystem != unique[j].ecosystem {
+			return unique[i].ecosystem < unique[j].ecosystem
+		}
+		return unique[i].name < unique[j].name
+	})
+	return unique, nil
+}
+
+func licenseAllowed(licenses []string, allowed map[string]bool) bool {
+	for _, alt := range licenses {
+		ok := true
+		for _, part := range strings.Split(alt, " AND ") {
+			if !allowed[strings.Trim(strings.TrimSpace(part), "()")] {
+				ok = false
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func licenseCategory(licenses []string) string {
+	if len(licenses) == 0 {
+		return "unknown"
+	}
+	for _, alt := range licenses {
+		for _, prefix := range copyleftLicensePrefixes {
+			if strings.HasPrefix(strings.ToUpper(alt), prefix) {
+				return "copyleft"
+			}
+		}
+	}
+	for _, alt := range licenses {
+		if alt == "proprietary" || alt == "UNLICENSED" || strings.HasPrefix(alt, "SEE LICENSE") {
+			return "unknown"
+		}
+	}
+	return "not allowed"
+}
+
+func loadProjectConfigFrom(projectPath string) error {
+	for _, name := range projectConfigFiles {
+		path := filepath.Join(projectPath, name)
+		if _, err := os.Stat(path); err == nil {
+			return loadProjectConfig(path)
+		}
+	}
+	return nil
+}
+
+func runLicenses(args []string) int {
+	fs := flag.NewFlagSet("licenses", flag.ContinueOnError)
+	path := fs.String("path", ".", "Path to the Drupal project")
+	var allow stringListFlag
+	fs.Var(&allow, "allow", "Additional allowed SPDX license ids (repeatable or comma-separated)")
+	all := fs.Bool("all", false, "List every package, not only flagged ones")
+	noDev := fs.Bool("no-dev", false, "Skip development dependencies")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if err := loadProjectConfigFrom(projectPath); err != nil {
+		return 1
+	}
+
+	deps, err := collectDependencies(projectPath)
+	if err != nil {
+		printError(fmt.Sprintf("Cannot read dependencies: %v", err))
+		return 1
+	}
+
+	allowed := map[string]bool{}
+	allowList := defaultAllowedLicenses
+	if len(project.Licenses.Allow) > 0 {
+		allowList = project.Licenses.Allow
+	}
+	for _, id := range append(append([]string(nil), allowList...), allow...) {
+		allowed[id] = true
+	}
+
+	counts := map[string]int{}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PACKAGE\tVERSION\tLICENSE\tSOURCE\tSTATUS")
+	flagged := 0
+	for _, dep := range deps {
+		if *noDev && dep.dev {
+			continue
+		}
+		license := strings.Join(dep.licenses, " OR ")
+		if license == "" {
+			license = "(none)"
+		}
+		counts[license]++
+		status := "ok"
+		if !licenseAllowed(dep.licenses, allowed) {
+			status = licenseCategory(dep.licenses)
+			flagged++
+		} else if !*all {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", dep.name, dep.version, license, dep.source, status)
+	}
+	if *all || flagged > 0 {
+		w.Flush()
+		fmt.Println()
+	}
+
+	licenses := make([]string, 0, len(counts))
+	for license := range counts {
+		licenses = append(licenses, license)
+	}
+	sort.Slice(licenses, func(i, j int) bool {
+		if counts[licenses[i]] != counts[licenses[j]] {
+			return counts[licenses[i]] > counts[licenses[j]]
+		}
+		return licenses[i] < licenses[j]
+	})
+	for _, license := range licenses {
+		fmt.Printf("%5d  %s\n", counts[license], license)
+	}
+	fmt.Println()
+
+	if flagged > 0 {
+		printError(fmt.Sprintf("%d packages have copyleft, unknown or non-allowed licenses", flagged))
+		return 1
+	}
+	printSuccess("✓ All package licenses are on the allowlist")
+	return 0
+}
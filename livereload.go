@@ -0,0 +1,101 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+type liveReloadTool struct {
+	port     int
+	httpPort int
+	config   string
+}
+
+var liveReloadTools = map[string]liveReloadTool{
+	"browsersync": {port: 3000, httpPort: 3001, config: "bs-config.js"},
+	"vite":        {port: 5173, httpPort: 5172, config: "vite.ddev.js"},
+}
+
+type liveReloadTemplateData struct {
+	Tool      string
+	Port      int
+	HTTPPort  int
+	ConfigDir string
+	Watch     []string
+}
+
+func runScaffoldLiveReload(args []string) int {
+	fs := flag.NewFlagSet("scaffold live-reload", flag.ContinueOnError)
+	toolName := fs.String("tool", "browsersync", "Dev server: browsersync or vite")
+	theme := fs.String("theme", "", "Custom theme to watch and run the dev server in (default: all custom themes and modules, from the project root)")
+	dir := fs.String("path", ".", "Directory inside the project")
+	force := fs.Bool("force", false, "Overwrite existing files")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	tool, ok := liveReloadTools[*toolName]
+	if !ok {
+		printError(fmt.Sprintf("Unknown --tool %q (expected browsersync or vite)", *toolName))
+		return 2
+	}
+	projectPath, err := findProjectRoot(*dir)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	docroot := projectDocroot(projectPath)
+	rel := "."
+	watch := []string{
+		docroot + "/themes/custom/**/*.{css,js,twig}",
+		docroot + "/modules/custom/**/*.{css,js,twig}",
+	}
+	if *theme != "" {
+		rel = path.Join(docroot, "themes", "custom", *theme)
+		if _, err := os.Stat(filepath.Join(projectPath, filepath.FromSlash(rel))); err != nil {
+			printError(fmt.Sprintf("Theme directory %s not found", rel))
+			return 1
+		}
+		watch = []string{"css/**/*.css", "js/**/*.js", "templates/**/*.twig", "*.theme"}
+	}
+	data := liveReloadTemplateData{
+		Tool:      *toolName,
+		Port:      tool.port,
+		HTTPPort:  tool.httpPort,
+		ConfigDir: path.Join("/var/www/html", rel),
+		Watch:     watch,
+	}
+
+	files := [][2]string{
+		{"live-reload-ddev.yaml", filepath.Join(projectPath, ".ddev", "config.live-reload.yaml")},
+		{path.Base(tool.config), filepath.Join(projectPath, filepath.FromSlash(rel), tool.config)},
+		{"ddev-" + *toolName, filepath.Join(projectPath, ".ddev", "commands", "web", *toolName)},
+	}
+	for i, f := range files {
+		content, err := renderScaffold(f[0], data)
+		if err != nil {
+			printError(fmt.Sprintf("Failed to render %s: %v", f[0], err))
+			return 1
+		}
+		write := writeScaffoldFile
+		if i == len(files)-1 {
+			write = writeScaffoldExecutable
+		}
+		if err := write(f[1], content, *force); err != nil {
+			return 1
+		}
+	}
+
+	fmt.Println()
+	fmt.Printf("Run 'ddev restart' once to publish port %d, then 'ddev %s'.\n", tool.port, *toolName)
+	if *toolName == "vite" {
+		fmt.Println("Use the server settings in vite.config.js: import ddevServer from './vite.ddev.js' and set server: ddevServer.")
+	} else {
+		fmt.Printf("Open https://<project>.ddev.site:%d; pages reload when watched files change.\n", tool.port)
+	}
+	fmt.Println("Twig changes need Twig caching off (settings.local.php and development.services.yml).")
+	return 0
+}
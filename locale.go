@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type localeSettings struct {
+	Timezone string `yaml:"timezone"`
+	Country  string `yaml:"country"`
+	FirstDay string `yaml:"first_day"`
+}
+
+var siteLocale struct {
+	timezone string
+	country  string
+	firstDay int
+}
+
+var weekdays = []string{"sunday", "monday", "tuesday", "wednesday", "thursday", "friday", "saturday"}
+
+var sundayFirstCountries = []string{"US", "CA", "MX", "BR", "JP", "KR", "IL", "PH", "ZA", "IN", "HK", "TW", "SA"}
+
+var (
+	countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+	langPattern    = regexp.MustCompile(`^[a-z]{2,3}_([A-Z]{2})`)
+)
+
+func hostTimezone() string {
+	if tz := os.Getenv("TZ"); tz != "" && !strings.HasPrefix(tz, ":") {
+		return tz
+	}
+	if target, err := os.Readlink("/etc/localtime"); err == nil {
+		if _, zone, ok := strings.Cut(filepath.ToSlash(target), "zoneinfo/"); ok {
+			return zone
+		}
+	}
+	return "UTC"
+}
+
+func hostCountry() string {
+	for _, name := range []string{"LC_ALL", "LC_TIME", "LANG"} {
+		if m := langPattern.FindStringSubmatch(os.Getenv(name)); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+func defaultFirstDay(country string) int {
+	if country == "" || containsString(sundayFirstCountries, country) {
+		return 0
+	}
+	return 1
+}
+
+func parseWeekday(value string) (int, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if n, err := strconv.Atoi(value); err == nil && n >= 0 && n <= 6 {
+		return n, nil
+	}
+	for i, day := range weekdays {
+		if value == day || (len(value) >= 3 && strings.HasPrefix(day, value)) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid first day of week %q (expected a weekday name or 0-6, 0 being Sunday)", value)
+}
+
+func resolveLocale() error {
+	timezone := firstNonEmpty(opts.timezone, project.Locale.Timezone)
+	country := strings.ToUpper(firstNonEmpty(opts.country, project.Locale.Country))
+	firstDay := firstNonEmpty(opts.firstDay, project.Locale.FirstDay)
+
+	if opts.interactive && timezone == "" {
+		timezone = promptLine(fmt.Sprintf("Site timezone [%s]: ", hostTimezone()))
+	}
+	if timezone == "" {
+		timezone = hostTimezone()
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return fmt.Errorf("unknown timezone %q (use an IANA name such as Europe/Berlin)", timezone)
+	}
+
+	if opts.interactive && country == "" {
+		country = strings.ToUpper(promptLine(fmt.Sprintf("Default country code [%s]: ", firstNonEmpty(hostCountry(), "none"))))
+		if country == "NONE" {
+			country = ""
+		}
+	}
+	if country == "" {
+		country = hostCountry()
+	}
+	if country != "" && !countryPattern.MatchString(country) {
+		return fmt.Errorf("invalid country %q (use a two-letter ISO code such as DE)", country)
+	}
+
+	day := defaultFirstDay(country)
+	if opts.interactive && firstDay == "" {
+		firstDay = promptLine(fmt.Sprintf("First day of the week [%s]: ", weekdays[day]))
+	}
+	if firstDay != "" {
+		parsed, err := parseWeekday(firstDay)
+		if err != nil {
+			return err
+		}
+		day = parsed
+	}
+
+	siteLocale.timezone, siteLocale.country, siteLocale.firstDay = timezone, country, day
+	printStatus(fmt.Sprintf("Site locale: timezone %s, country %s, week starts on %s",
+		timezone, firstNonEmpty(country, "none"), weekdays[day]))
+	return nil
+}
+
+func localeInstallArgs() []string {
+	if siteLocale.timezone == "" {
+		return nil
+	}
+	args := []string{"install_configure_form.date_default_timezone=" + siteLocale.timezone}
+	if siteLocale.country != "" {
+		args = append(args, "install_configure_form.site_default_country="+siteLocale.country)
+	}
+	return args
+}
+
+func applyFirstDay(projectPath string) error {
+	if siteLocale.timezone == "" {
+		return nil
+	}
+	return runDDEVQuiet(projectPath, "drush", "config:set", "system.date", "first_day", strconv.Itoa(siteLocale.firstDay), "--yes")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
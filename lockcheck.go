@@ -0,0 +1,276 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf16"
+)
+
+type installedPackages struct {
+	Packages []lockedPackage `json:"packages"`
+	Dev      bool            `json:"dev"`
+}
+
+func platformPackage(name string) bool {
+	return name == "php" || strings.HasPrefix(name, "php-") || strings.HasPrefix(name, "ext-") ||
+		strings.HasPrefix(name, "lib-") || name == "composer-plugin-api" || name == "composer-runtime-api" ||
+		name == "composer"
+}
+
+func composerCommand(projectPath string, args ...string) *exec.Cmd {
+	if _, err := os.Stat(filepath.Join(projectPath, ".ddev", "config.yaml")); err == nil {
+		cmd := exec.Command("ddev", append([]string{"composer"}, args...)...)
+		cmd.Dir = projectPath
+		return cmd
+	}
+	cmd := exec.Command("composer", append(args, "--working-dir="+projectPath)...)
+	cmd.Dir = projectPath
+	return cmd
+}
+
+var contentHashKeys = []string{"name", "version", "require", "require-dev", "conflict", "replace", "provide", "minimum-stability", "prefer-stable", "repositories", "extra"}
+
+func composerContentHash(composer *jsonObject) string {
+	relevant := newJSONObject()
+	for _, key := range contentHashKeys {
+		if value, ok := composer.values[key]; ok {
+			relevant.set(key, value)
+		}
+	}
+	if config, ok := composer.values["config"].(*jsonObject); ok {
+		if platform, ok := config.values["platform"]; ok {
+			relevant.object("config").set("platform", platform)
+		}
+	}
+	sort.Strings(relevant.keys)
+	var buf strings.Builder
+	writePHPJSON(&buf, relevant)
+	return fmt.Sprintf("%x", md5.Sum([]byte(buf.String())))
+}
+
+func writePHPJSON(buf *strings.Builder, v any) {
+	switch v := v.(type) {
+	case *jsonObject:
+		if len(v.keys) == 0 {
+			buf.WriteString("[]")
+			return
+		}
+		buf.WriteByte('{')
+		for i, key := range v.keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writePHPJSON(buf, key)
+			buf.WriteByte(':')
+			writePHPJSON(buf, v.values[key])
+		}
+		buf.WriteByte('}')
+	case []any:
+		buf.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writePHPJSON(buf, item)
+		}
+		buf.WriteByte(']')
+	case string:
+		buf.WriteByte('"')
+		for _, r := range v {
+			switch {
+			case r == '"' || r == '\\' || r == '/':
+				buf.WriteByte('\\')
+				buf.WriteRune(r)
+			case r == '\b':
+				buf.WriteString(`\b`)
+			case r == '\f':
+				buf.WriteString(`\f`)
+			case r == '\n':
+				buf.WriteString(`\n`)
+			case r == '\r':
+				buf.WriteString(`\r`)
+			case r == '\t':
+				buf.WriteString(`\t`)
+			case r < 0x20 || r > 0x7f:
+				for _, unit := range utf16.Encode([]rune{r}) {
+					fmt.Fprintf(buf, `\u%04x`, unit)
+				}
+			default:
+				buf.WriteRune(r)
+			}
+		}
+		buf.WriteByte('"')
+	case bool:
+		fmt.Fprint(buf, v)
+	case nil:
+		buf.WriteString("null")
+	default:
+		fmt.Fprint(buf, v)
+	}
+}
+
+func checkLockInSync(projectPath string, lock *composerLock) error {
+	composer, err := readJSONFile(filepath.Join(projectPath, "composer.json"))
+	if err != nil {
+		return err
+	}
+	locked := map[string]bool{}
+	for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+		locked[pkg.Name] = true
+	}
+	var missing []string
+	for _, section := range []string{"require", "require-dev"} {
+		require, ok := composer.values[section].(*jsonObject)
+		if !ok {
+			continue
+		}
+		for _, name := range require.keys {
+			if !platformPackage(name) && !locked[name] {
+				missing = append(missing, name)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("required but not in composer.lock: %s (run 'composer update %s')", strings.Join(missing, ", "), strings.Join(missing, " "))
+	}
+
+	outdated := fmt.Errorf("composer.lock content hash does not match composer.json (run 'composer update --lock')")
+	if lock.ContentHash != "" && lock.ContentHash != composerContentHash(composer) {
+		return outdated
+	}
+
+	cmd := composerCommand(projectPath, "validate", "--no-check-all", "--no-check-publish", "--no-interaction")
+	if dryRunCommand(cmd) {
+		return nil
+	}
+	output, err := combinedOutput(cmd)
+	if strings.Contains(string(output), "lock file is not up to date") {
+		return outdated
+	}
+	if err != nil {
+		return fmt.Errorf("composer validate failed: %v\n%s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+func checkVendorMatchesLock(projectPath string, lock *composerLock) error {
+	data, err := os.ReadFile(filepath.Join(projectPath, "vendor", "composer", "installed.json"))
+	if err != nil {
+		return fmt.Errorf("vendor is not installed (run 'composer install')")
+	}
+	var installed installedPackages
+	if err := json.Unmarshal(data, &installed); err != nil {
+		return fmt.Errorf("vendor/composer/installed.json: %v", err)
+	}
+	byName := map[string]lockedPackage{}
+	for _, pkg := range installed.Packages {
+		byName[pkg.Name] = pkg
+	}
+
+	expected := lock.Packages
+	if installed.Dev {
+		expected = append(expected, lock.PackagesDev...)
+	}
+	var problems []string
+	for _, want := range expected {
+		got, ok := byName[want.Name]
+		switch {
+		case !ok:
+			problems = append(problems, want.Name+" missing")
+		case got.Version != want.Version:
+			problems = append(problems, fmt.Sprintf("%s %s installed, %s locked", want.Name, got.Version, want.Version))
+		case packageReference(got) != packageReference(want):
+			problems = append(problems, fmt.Sprintf("%s %s built from %s, locked to %s", want.Name, want.Version, shortRef(packageReference(got)), shortRef(packageReference(want))))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("vendor differs from composer.lock (run 'composer install'): %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+func packageReference(pkg lockedPackage) string {
+	if pkg.Dist.Reference != "" {
+		return pkg.Dist.Reference
+	}
+	return pkg.Source.Reference
+}
+
+func shortRef(ref string) string {
+	if len(ref) > 10 {
+		return ref[:10]
+	}
+	return ref
+}
+
+func checkNoAbandoned(lock *composerLock) error {
+	var abandoned []string
+	for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+		switch v := pkg.Abandoned.(type) {
+		case bool:
+			if v {
+				abandoned = append(abandoned, pkg.Name)
+			}
+		case string:
+			abandoned = append(abandoned, fmt.Sprintf("%s (use %s)", pkg.Name, v))
+		}
+	}
+	if len(abandoned) > 0 {
+		sort.Strings(abandoned)
+		return fmt.Errorf("abandoned packages: %s", strings.Join(abandoned, ", "))
+	}
+	return nil
+}
+
+func runVerifyLock(args []string) int {
+	fs := flag.NewFlagSet("verify-lock", flag.ContinueOnError)
+	path := fs.String("path", ".", "Path to the Composer project")
+	allowAbandoned := fs.Bool("allow-abandoned", false, "Report abandoned packages without failing")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	lock, err := loadComposerLock(projectPath)
+	if err != nil {
+		printError(fmt.Sprintf("Cannot read composer.lock: %v", err))
+		return 1
+	}
+
+	checks := []verifyCheck{
+		{name: "composer.lock matches composer.json", run: func() error { return checkLockInSync(projectPath, lock) }},
+		{name: "vendor matches composer.lock", run: func() error { return checkVendorMatchesLock(projectPath, lock) }},
+		{id: "abandoned", name: "No abandoned packages", run: func() error { return checkNoAbandoned(lock) }},
+	}
+
+	failed := 0
+	for _, check := range checks {
+		if err := check.run(); err != nil {
+			if check.id == "abandoned" && *allowAbandoned {
+				printWarning(fmt.Sprintf("! %s: %v", check.name, err))
+				continue
+			}
+			printError(fmt.Sprintf("✗ %s: %v", check.name, err))
+			failed++
+			continue
+		}
+		printSuccess(fmt.Sprintf("✓ %s", check.name))
+	}
+	if failed > 0 {
+		printError(fmt.Sprintf("Lock verification failed: %d of %d checks did not pass", failed, len(checks)))
+		return 1
+	}
+	printSuccess(fmt.Sprintf("✓ composer.lock verified (%d packages)", len(lock.Packages)+len(lock.PackagesDev)))
+	return 0
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var lockSnapshot string
+
+func lockSnapshotsDir() (string, error) {
+	dir, err := userConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "locks"), nil
+}
+
+func resolveLockSnapshot(ref string) (string, error) {
+	dir := ref
+	if info, err := os.Stat(ref); err == nil && !info.IsDir() {
+		dir = filepath.Dir(ref)
+	} else if err != nil {
+		snapshots, err := lockSnapshotsDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(snapshots, ref)
+	}
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for _, name := range []string{"composer.json", "composer.lock"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return "", fmt.Errorf("lock snapshot %q has no %s (looked in %s)", ref, name, dir)
+		}
+	}
+	return dir, nil
+}
+
+func loadLockSnapshot() error {
+	ref := opts.fromLock
+	if ref == "" {
+		ref = project.Composer.FromLock
+	}
+	if ref == "" {
+		return nil
+	}
+	dir, err := resolveLockSnapshot(ref)
+	if err != nil {
+		return err
+	}
+	lockSnapshot = dir
+	printStatus(fmt.Sprintf("Building from lock snapshot %s", dir))
+	return nil
+}
+
+func copyComposerFiles(src, dst string) error {
+	for _, name := range []string{"composer.json", "composer.lock"} {
+		data, err := os.ReadFile(filepath.Join(src, name))
+		if err != nil {
+			return err
+		}
+		if err := writeFile(filepath.Join(dst, name), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unlockedPackages(projectPath string, packages []string) ([]string, error) {
+	versions, err := readComposerLock(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	var missing []string
+	for _, pkg := range packages {
+		name, _, _ := strings.Cut(pkg, ":")
+		if _, ok := versions[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	return missing, nil
+}
+
+func runLock(args []string) int {
+	if len(args) == 0 || (args[0] != "save" && args[0] != "list") {
+		printError("Usage: install-drupal lock save [--path dir] [--force] <name> | install-drupal lock list")
+		return 2
+	}
+	snapshots, err := lockSnapshotsDir()
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	if args[0] == "list" {
+		entries, _ := os.ReadDir(snapshots)
+		var names []string
+		for _, entry := range entries {
+			if entry.IsDir() {
+				names = append(names, entry.Name())
+			}
+		}
+		sort.Strings(names)
+		if len(names) == 0 {
+			printStatus("No lock snapshots saved")
+			return 0
+		}
+		for _, name := range names {
+			lock, err := loadComposerLock(filepath.Join(snapshots, name))
+			if err != nil {
+				printWarning(fmt.Sprintf("%s: %v", name, err))
+				continue
+			}
+			fmt.Printf("%-24s %4d packages  %s\n", name, len(lock.Packages)+len(lock.PackagesDev), lock.ContentHash)
+		}
+		return 0
+	}
+
+	fs := flag.NewFlagSet("lock save", flag.ContinueOnError)
+	path := fs.String("path", ".", "Project whose composer.json and composer.lock are saved")
+	force := fs.Bool("force", false, "Replace an existing snapshot with the same name")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 || strings.ContainsAny(fs.Arg(0), `/\`) {
+		printError("Usage: install-drupal lock save [--path dir] [--force] <name>")
+		return 2
+	}
+	name := fs.Arg(0)
+	dest := filepath.Join(snapshots, name)
+	if _, err := os.Stat(dest); err == nil && !*force {
+		printError(fmt.Sprintf("Lock snapshot %s already exists (use --force to replace it)", name))
+		return 1
+	}
+	if _, err := loadComposerLock(*path); err != nil {
+		printError(fmt.Sprintf("Cannot read composer.lock: %v", err))
+		return 1
+	}
+	if err := ensureDir(dest); err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if err := copyComposerFiles(*path, dest); err != nil {
+		printError(fmt.Sprintf("Failed to save lock snapshot: %v", err))
+		return 1
+	}
+	printSuccess(fmt.Sprintf("✓ Saved lock snapshot %s", name))
+	printStatus(fmt.Sprintf("Build from it with: install-drupal --from-lock %s", name))
+	return 0
+}
+
+func installFromLockSnapshot(projectPath string) error {
+	missing, err := unlockedPackages(projectPath, composerPackages)
+	if err != nil {
+		printError("Failed to read composer.lock from the snapshot")
+		return err
+	}
+	if len(missing) > 0 {
+		printError("The lock snapshot does not contain: " + strings.Join(missing, ", "))
+		printStatus("Add them to the project the snapshot was taken from and save it again with 'install-drupal lock save --force'")
+		return fmt.Errorf("packages missing from lock snapshot")
+	}
+	if !opts.quick {
+		if missing, _ := unlockedPackages(projectPath, []string{"drupal/core-dev"}); len(missing) > 0 {
+			printWarning("The lock snapshot has no drupal/core-dev; development tools will not be installed")
+		}
+	}
+	if err := runDDEV(projectPath, "composer", "install", "--no-interaction"); err != nil {
+		printError("Failed to install from the lock snapshot")
+		return err
+	}
+	printSuccess("✓ Drupal dependencies installed from lock snapshot")
+	return nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type verbosityFlag struct {
+	verbose *int
+	level   int
+}
+
+func (f *verbosityFlag) String() string {
+	return strconv.FormatBool(f.verbose != nil && *f.verbose >= f.level)
+}
+
+func (f *verbosityFlag) Set(value string) error {
+	v, err := strconv.ParseBool(value)
+	if err != nil || !v {
+		return err
+	}
+	*f.verbose = max(*f.verbose+1, f.level)
+	return nil
+}
+
+func (f *verbosityFlag) IsBoolFlag() bool {
+	return true
+}
+
+var logFile *os.File
+
+func openLogFile() error {
+	if opts.logFile == "" {
+		return nil
+	}
+	f, err := os.OpenFile(opts.logFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, secretPerm)
+	if err != nil {
+		printError(fmt.Sprintf("Cannot open --log-file: %v", err))
+		return err
+	}
+	logFile = f
+	fmt.Fprintf(logFile, "\n==== install-drupal started at %s\n", time.Now().Format(time.DateTime))
+	return nil
+}
+
+func closeLogFile() {
+	if logFile == nil {
+		return
+	}
+	logFile.Close()
+	logFile = nil
+}
+
+func logLine(label, msg string) {
+	if logFile == nil {
+		return
+	}
+	fmt.Fprintf(logFile, "%s [%s] %s\n", time.Now().Format(time.TimeOnly), label, msg)
+}
+
+func traceCommand(cmd *exec.Cmd) {
+	if opts.verbose >= 1 {
+		printLabeled(colorBlue, "RUN", commandLine(cmd))
+		return
+	}
+	logLine("RUN", commandLine(cmd))
+}
+
+func logCommandResult(cmd *exec.Cmd, err error) {
+	if err != nil {
+		logLine("EXIT", fmt.Sprintf("%s: %v", commandLine(cmd), err))
+		return
+	}
+	logLine("EXIT", commandLine(cmd)+": ok")
+}
+
+func teeToLog(w io.Writer) io.Writer {
+	if logFile == nil {
+		return w
+	}
+	return addWriter(w, logFile)
+}
+
+func addWriter(w, extra io.Writer) io.Writer {
+	if w == nil {
+		return extra
+	}
+	return io.MultiWriter(w, extra)
+}
+
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Bytes()
+}
+
+func startCommand(cmd *exec.Cmd, capture io.Writer) error {
+	traceCommand(cmd)
+	var held *lockedBuffer
+	if quietCommand(cmd) {
+		held = &lockedBuffer{}
+		cmd.Stdout, cmd.Stderr = held, held
+	}
+	cmd.Stdout = teeToLog(cmd.Stdout)
+	cmd.Stderr = teeToLog(cmd.Stderr)
+	if capture != nil {
+		cmd.Stdout = addWriter(cmd.Stdout, capture)
+		cmd.Stderr = addWriter(cmd.Stderr, capture)
+	}
+	var spin *spinner
+	if held != nil {
+		spin = startSpinner(commandArgs(cmd))
+	}
+	err := cmd.Run()
+	spin.stop()
+	if err != nil && held != nil {
+		os.Stderr.Write(held.Bytes())
+	}
+	logCommandResult(cmd, err)
+	return err
+}
+
+func commandOutput(cmd *exec.Cmd) ([]byte, error) {
+	traceCommand(cmd)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	if cmd.Stderr == nil {
+		cmd.Stderr = &stderr
+	} else {
+		cmd.Stderr = teeToLog(cmd.Stderr)
+	}
+	err := cmd.Run()
+	showCapturedOutput(stdout.Bytes())
+	showCapturedOutput(stderr.Bytes())
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		exitErr.Stderr = stderr.Bytes()
+	}
+	logCommandResult(cmd, err)
+	return stdout.Bytes(), err
+}
+
+func combinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	traceCommand(cmd)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := cmd.Run()
+	showCapturedOutput(output.Bytes())
+	logCommandResult(cmd, err)
+	return output.Bytes(), err
+}
+
+func showCapturedOutput(output []byte) {
+	if len(output) == 0 {
+		return
+	}
+	if opts.verbose >= 2 {
+		os.Stdout.Write(output)
+	}
+	if logFile != nil {
+		logFile.Write(output)
+		if output[len(output)-1] != '\n' {
+			logFile.WriteString("\n")
+		}
+	}
+}
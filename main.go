@@ -1,46 +1,16 @@
 package main
 
 import (
-	"bufio"
-	_ "embed"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
-//go:embed config/environment_indicator.indicator.yml
-var configIndicatorYML string
-
-//go:embed config/environment_indicator.settings.yml
-var configSettingsYML string
-
-const (
-	colorRed    = "\033[0;31m"
-	colorGreen  = "\033[0;32m"
-	colorYellow = "\033[1;33m"
-	colorBlue   = "\033[0;34m"
-	colorReset  = "\033[0m"
-)
-
-func printStatus(msg string) {
-	fmt.Printf("%s[INFO]%s %s\n", colorBlue, colorReset, msg)
-}
-
-func printSuccess(msg string) {
-	fmt.Printf("%s[SUCCESS]%s %s\n", colorGreen, colorReset, msg)
-}
-
-func printWarning(msg string) {
-	fmt.Printf("%s[WARNING]%s %s\n", colorYellow, colorReset, msg)
-}
-
-func printError(msg string) {
-	fmt.Printf("%s[ERROR]%s %s\n", colorRed, colorReset, msg)
-}
-
 func commandExists(cmd string) bool {
 	_, err := exec.LookPath(cmd)
 	return err == nil
@@ -50,12 +20,15 @@ func runCommand(name string, args ...string) error {
 	cmd := exec.Command(name, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	return runCmd(cmd)
 }
 
 func runCommandOutput(name string, args ...string) (string, error) {
 	cmd := exec.Command(name, args...)
-	output, err := cmd.CombinedOutput()
+	if dryRunCommand(cmd) {
+		return "", nil
+	}
+	output, err := combinedOutput(cmd)
 	return string(output), err
 }
 
@@ -82,7 +55,7 @@ func installDocker() bool {
 		return true
 	}
 	printStatus("Docker Desktop not found. Installing via Homebrew...")
-	if err := runCommand("brew", "install", "docker"); err != nil {
+	if err := brewInstallVerified("install", "docker"); err != nil {
 		printError("Failed to install Docker Desktop")
 		return false
 	}
@@ -109,7 +82,7 @@ func installColima() bool {
 		return true
 	}
 	printStatus("Colima not found. Installing via Homebrew...")
-	if err := runCommand("brew", "install", "colima"); err != nil {
+	if err := brewInstallVerified("install", "colima"); err != nil {
 		printError("Failed to install Colima")
 		return false
 	}
@@ -135,7 +108,12 @@ func startColima() {
 		printSuccess("Colima is already running")
 		return
 	}
-	if err := runCommand("colima", "start"); err != nil {
+	args := []string{"start"}
+	if !colimaConfigured() {
+		args = append(args, colimaStartArgs(detectHost())...)
+		printStatus(fmt.Sprintf("Creating Colima VM with: colima %s", strings.Join(args, " ")))
+	}
+	if err := runCommand("colima", args...); err != nil {
 		printError("Failed to start Colima")
 		return
 	}
@@ -149,7 +127,7 @@ func installDDEV() bool {
 		return true
 	}
 	printStatus("DDEV not found. Installing via Homebrew...")
-	if err := runCommand("brew", "install", "ddev/ddev/ddev"); err != nil {
+	if err := brewInstallVerified("install", "ddev/ddev/ddev"); err != nil {
 		printError("Failed to install DDEV")
 		return false
 	}
@@ -157,11 +135,23 @@ func installDDEV() bool {
 	return false
 }
 
-func checkDDEVVersion() {
-	if commandExists("ddev") {
-		output, _ := runCommandOutput("ddev", "version")
-		printSuccess(fmt.Sprintf("DDEV version: %s", strings.TrimSpace(output)))
+func checkDDEVVersion() error {
+	if !commandExists("ddev") {
+		return nil
 	}
+
+	version := installedDDEVVersion()
+	printSuccess(fmt.Sprintf("DDEV version: %s", version))
+	if version == "" || compareVersions(version, minDDEVVersion) >= 0 {
+		return nil
+	}
+
+	printWarning(fmt.Sprintf("DDEV %s is older than the minimum supported version %s", version, minDDEVVersion))
+	if !opts.upgradeDDEV && (!opts.interactive || !confirm("Upgrade DDEV with Homebrew now?", false)) {
+		printError(fmt.Sprintf("Please upgrade DDEV to %s or newer (brew upgrade ddev/ddev/ddev), or pass --upgrade-ddev", minDDEVVersion))
+		return fmt.Errorf("ddev %s is too old", version)
+	}
+	return upgradeDDEV()
 }
 
 func checkPrerequisites(dockerProvider string) {
@@ -195,39 +185,66 @@ func checkPrerequisites(dockerProvider string) {
 		printWarning("✗ DDEV is not installed")
 	}
 
+	warnArchitectureMismatches()
+
 	fmt.Println()
 }
 
 func initDrupalProject() (string, error) {
 	printStatus("Initializing Drupal project...")
 
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Println()
-	fmt.Print("Enter your Drupal project name (e.g., 'my-drupal-site'): ")
-	projectName, _ := reader.ReadString('\n')
-	projectName = strings.TrimSpace(projectName)
+	projectName := opts.projectName
+	if projectName == "" && opts.interactive {
+		fmt.Println()
+		projectName = promptLine("Enter your Drupal project name (e.g., 'my-drupal-site'): ")
+	}
 
 	if projectName == "" {
 		printError("Project name cannot be empty")
 		return "", fmt.Errorf("empty project name")
 	}
 
-	projectName = strings.ToLower(projectName)
-	projectName = strings.ReplaceAll(projectName, " ", "-")
+	projectName = normalizeProjectName(projectName)
 
-	cwd, err := os.Getwd()
+	parent, err := projectParentDir()
 	if err != nil {
-		printError("Failed to get current directory")
+		printError(fmt.Sprintf("Failed to choose the project directory: %v", err))
 		return "", err
 	}
 
-	projectPath := filepath.Join(cwd, projectName)
-
-	printStatus(fmt.Sprintf("Creating Drupal project: %s", projectName))
-	if err := runCommand("composer", "create-project", "drupal/recommended-project:^11", projectPath); err != nil {
-		printError("Failed to create Drupal project")
+	projectPath, adopted, err := resolveProjectDir(parent, projectName)
+	if err != nil {
+		printError(err.Error())
 		return "", err
 	}
+	projectName = opts.projectName
+	if onWindowsMount(projectPath) {
+		printWarning("Project is on a Windows filesystem mount; file permissions are not enforced and DDEV performance will be poor. Consider a path inside the Linux filesystem.")
+	}
+
+	if adopted {
+		printStatus(fmt.Sprintf("Skipping composer create-project; continuing with %s", projectPath))
+	} else {
+		printStatus(fmt.Sprintf("Creating Drupal project: %s", projectName))
+		createArgs := []string{"create-project", "drupal/recommended-project:" + drupalVersion, projectPath}
+		if lockSnapshot != "" {
+			createArgs = append(createArgs, "--no-install")
+		}
+		if err := runCommand("composer", createArgs...); err != nil {
+			printError("Failed to create Drupal project")
+			return "", err
+		}
+	}
+	if lockSnapshot != "" {
+		if err := copyComposerFiles(lockSnapshot, projectPath); err != nil {
+			printError("Failed to copy the lock snapshot into the project")
+			return "", err
+		}
+	}
+	recordAnswer("project-name", projectName)
+	if adopted {
+		recordAnswer("if-exists", ifExistsAdopt)
+	}
 	printSuccess(fmt.Sprintf("✓ Drupal project '%s' initialized", projectName))
 
 	return projectPath, nil
@@ -237,33 +254,47 @@ func setupDrupalSettings(projectPath string) error {
 	printStatus("Setting up Drupal settings...")
 
 	configSyncPath := filepath.Join(projectPath, "config", "sync")
-	if err := os.MkdirAll(configSyncPath, 0755); err != nil {
+	if err := ensureDir(configSyncPath); err != nil {
 		printError("Failed to create config directory")
 		return err
 	}
 
-	settingsPath := filepath.Join(projectPath, "web", "sites", "default", "settings.ddev.php")
-	content, err := os.ReadFile(settingsPath)
-	if err != nil {
-		printError("Failed to read settings.ddev.php")
+	if err := writeSettingsChain(projectPath); err != nil {
+		return err
+	}
+
+	if err := configurePrivateFiles(projectPath); err != nil {
 		return err
 	}
 
-	newContent := strings.ReplaceAll(string(content), "sites/default/files/sync", "../config/sync")
-	if err := os.WriteFile(settingsPath, []byte(newContent), 0644); err != nil {
-		printError("Failed to write settings.ddev.php")
+	if err := prepareExistingConfig(projectPath, configSyncPath); err != nil {
 		return err
 	}
 
-	indicatorPath := filepath.Join(configSyncPath, "environment_indicator.indicator.yml")
-	if err := os.WriteFile(indicatorPath, []byte(configIndicatorYML), 0644); err != nil {
-		printError("Failed to write config files")
+	if err := ensureDir(filepath.Join(projectPath, "config", "local")); err != nil {
+		printError("Failed to create config split directory")
+		return err
+	}
+
+	if err := loadConfigTemplates(); err != nil {
+		printError("Failed to load config templates")
+		return err
+	}
+
+	if opts.quick {
+		printSuccess("✓ Drupal settings setup completed (quick mode: seed configuration skipped)")
+		return nil
+	}
+	if existingConfig {
+		printSuccess("✓ Drupal settings setup completed (existing configuration: seed configuration skipped)")
+		return nil
+	}
+
+	if err := renderConfigTemplates(configSyncPath, opts.configVars); err != nil {
 		return err
 	}
 
-	settingsConfigPath := filepath.Join(configSyncPath, "environment_indicator.settings.yml")
-	if err := os.WriteFile(settingsConfigPath, []byte(configSettingsYML), 0644); err != nil {
-		printError("Failed to write config files")
+	if err := applyConfigOverrides(configSyncPath); err != nil {
 		return err
 	}
 
@@ -285,11 +316,16 @@ func initDDEVProject(projectPath string) error {
 		return nil
 	}
 
-	cmd := exec.Command("ddev", "config", "--project-type=drupal11", "--docroot=web", "--create-docroot")
+	projectType, docroot := ddevProjectSettings(projectPath)
+	args := []string{"config", "--project-type=" + projectType, "--docroot=" + docroot, "--create-docroot", "--project-name=" + ddevProjectName(projectPath)}
+	if project.DDEV.PHPVersion != "" {
+		args = append(args, "--php-version="+project.DDEV.PHPVersion)
+	}
+	cmd := exec.Command("ddev", args...)
 	cmd.Dir = projectPath
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := runCmd(cmd); err != nil {
 		printError("Failed to initialize DDEV project")
 		return err
 	}
@@ -299,46 +335,128 @@ func initDDEVProject(projectPath string) error {
 
 func startDDEV(projectPath string) error {
 	printStatus("Starting DDEV...")
-	cmd := exec.Command("ddev", "start")
-	cmd.Dir = projectPath
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	for {
+		cmd := exec.Command("ddev", "start")
+		cmd.Dir = projectPath
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		output, err := runCmdCapture(cmd)
+		if err == nil {
+			break
+		}
 		printError("Failed to start DDEV")
-		return err
+		if !offerDDEVRecovery(projectPath, output) {
+			return err
+		}
+		printStatus("Starting DDEV again...")
 	}
 	printSuccess("DDEV started")
 	return nil
 }
 
+var composerPackages = []string{
+	"drush/drush",
+	"drupal/admin_toolbar",
+	"drupal/token",
+	"drupal/pathauto",
+	"drupal/config_ignore",
+	"drupal/config_split",
+	"drupal/devel",
+	"drupal/environment_indicator",
+	"drupal/better_exposed_filters",
+	"drupal/key",
+	"drupal/webprofiler",
+	"drupal/diff:^2.0@beta",
+	"drupal/ultimate_cron:^2.0@beta",
+}
+
+var drupalModules = []string{
+	"admin_toolbar", "config_split", "devel", "environment_indicator",
+	"environment_indicator_ui", "environment_indicator_toolbar",
+	"token", "pathauto", "config_ignore", "better_exposed_filters",
+	"key", "webprofiler", "diff", "ultimate_cron", "devel_generate",
+}
+
+var defaultPresets = []string{"ckeditor", "search"}
+
+var quickModeSkippedModules = []string{"devel", "webprofiler"}
+
+func removeFromInstall(module string) []string {
+	var removed []string
+
+	var packages []string
+	removedPackage := false
+	for _, pkg := range composerPackages {
+		if packageName(pkg) == "drupal/"+module {
+			removed = append(removed, pkg)
+			removedPackage = true
+			continue
+		}
+		packages = append(packages, pkg)
+	}
+	composerPackages = packages
+
+	var modules []string
+	for _, m := range drupalModules {
+		if m == module || (removedPackage && strings.HasPrefix(m, module+"_")) {
+			removed = append(removed, m)
+			continue
+		}
+		modules = append(modules, m)
+	}
+	drupalModules = modules
+
+	return removed
+}
+
+func runDDEV(projectPath string, args ...string) error {
+	cmd := ddevCommand(projectPath, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return runCmd(cmd)
+}
+
+func ddevOutput(projectPath string, args ...string) ([]byte, error) {
+	cmd := ddevCommand(projectPath, args...)
+	cmd.Stderr = os.Stderr
+	if dryRunCommand(cmd) {
+		return nil, errDryRun
+	}
+	return commandOutput(cmd)
+}
+
 func installDrupalDependencies(projectPath string) error {
 	printStatus("Installing Drupal dependencies with Composer...")
 
-	packages := [][]string{
-		{"composer", "install"},
-		{"composer", "require", "drupal/core-dev", "--dev", "-W"},
-		{"composer", "require", "drush/drush"},
-		{"composer", "require", "drupal/admin_toolbar"},
-		{"composer", "require", "drupal/token"},
-		{"composer", "require", "drupal/pathauto"},
-		{"composer", "require", "drupal/config_ignore"},
-		{"composer", "require", "drupal/config_split"},
-		{"composer", "require", "drupal/devel"},
-		{"composer", "require", "drupal/environment_indicator"},
-		{"composer", "require", "drupal/better_exposed_filters"},
-		{"composer", "require", "drupal/key"},
-		{"composer", "require", "drupal/webprofiler"},
-		{"composer", "require", "drupal/diff:^2.0@beta"},
-		{"composer", "require", "drupal/ultimate_cron:^2.0@beta"},
-	}
-
-	for _, pkg := range packages {
-		cmd := exec.Command("ddev", pkg...)
-		cmd.Dir = projectPath
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		if err := cmd.Run(); err != nil {
-			printError(fmt.Sprintf("Failed to install %v", pkg))
+	if lockSnapshot != "" {
+		return installFromLockSnapshot(projectPath)
+	}
+
+	commands := [][]string{{"composer", "update", "--lock", "--no-install"}, {"composer", "install"}}
+	if !opts.quick {
+		commands = append(commands, []string{"composer", "require", "drupal/core-dev", "--dev", "-W"})
+	}
+	lockOnly := opts.composerLockOnly || project.Composer.LockOnly
+	for _, pkg := range composerPackages {
+		args := []string{"composer", "require", pkg}
+		if lockOnly {
+			args = append(args, "--no-install")
+		}
+		commands = append(commands, args)
+	}
+	if lockOnly {
+		commands = append(commands, []string{"composer", "install"})
+	}
+
+	for _, args := range commands {
+		if err := runDDEV(projectPath, args...); err != nil {
+			printError(fmt.Sprintf("Failed to install %v", args))
+			return err
+		}
+	}
+
+	if policy, _ := constraintPolicy(); policy == constraintExact {
+		if err := pinAndRelock(projectPath, false); err != nil {
 			return err
 		}
 	}
@@ -350,37 +468,43 @@ func installDrupalDependencies(projectPath string) error {
 func installDrupalSite(projectPath string) error {
 	printStatus("Installing Drupal site...")
 
-	cmd := exec.Command("ddev", "drush", "site:install", "standard", "--yes",
-		"--account-name=admin", "--account-pass=admin", "--site-name=Super Awesome Site")
-	cmd.Dir = projectPath
+	args := []string{"drush", "site:install", siteProfile(), "--yes",
+		"--account-name=admin", "--account-pass=" + opts.adminPassword, "--site-name=" + opts.siteName}
+	args = append(args, localeInstallArgs()...)
+	if existingConfig {
+		args = []string{"drush", "site:install", "--existing-config", "--yes",
+			"--account-name=admin", "--account-pass=" + opts.adminPassword}
+	}
+	cmd := ddevCommand(projectPath, args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		printError("Failed to install Drupal site")
-		return err
+	if err := runCmd(cmd); err != nil {
+		if !existingConfig {
+			printError("Failed to install Drupal site")
+			return err
+		}
+		printWarning("site:install --existing-config failed; falling back to a regular install plus config import")
+		if err := installExportedSite(projectPath); err != nil {
+			printError(fmt.Sprintf("Failed to install Drupal site from config/sync: %v", err))
+			return err
+		}
+	}
+	if !existingConfig {
+		if err := applyFirstDay(projectPath); err != nil {
+			printWarning("Failed to set the first day of the week")
+		}
 	}
 
 	printSuccess("Drupal site installed")
-	printStatus("Admin credentials: username=admin, password=admin")
+	printStatus(fmt.Sprintf("Admin credentials: username=admin, password=%s", opts.adminPassword))
 	return nil
 }
 
 func enableDrupalModules(projectPath string) error {
 	printStatus("Enabling Drupal modules...")
 
-	modules := []string{
-		"admin_toolbar", "config_split", "devel", "environment_indicator",
-		"environment_indicator_ui", "environment_indicator_toolbar",
-		"token", "pathauto", "config_ignore", "better_exposed_filters",
-		"key", "webprofiler", "diff", "ultimate_cron", "devel_generate",
-	}
-
-	args := append([]string{"drush", "en", "-y"}, modules...)
-	cmd := exec.Command("ddev", args...)
-	cmd.Dir = projectPath
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	args := append([]string{"drush", "en", "-y"}, drupalModules...)
+	if err := runDDEV(projectPath, args...); err != nil {
 		printError("Failed to enable modules")
 		return err
 	}
@@ -398,11 +522,19 @@ func importDrupalConfig(projectPath string) error {
 		return nil
 	}
 
-	cmd := exec.Command("ddev", "drush", "config:import", "--partial", "--yes")
-	cmd.Dir = projectPath
+	changes, err := validateConfigImport(projectPath, "", true)
+	if err != nil {
+		printError(err.Error())
+		return err
+	}
+	if changes == 0 {
+		return nil
+	}
+
+	cmd := ddevCommand(projectPath, "drush", "config:import", "--partial", "--yes")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := runCmd(cmd); err != nil {
 		printError("Failed to import config")
 		return err
 	}
@@ -412,32 +544,40 @@ func importDrupalConfig(projectPath string) error {
 }
 
 func generateDrupalContent(projectPath string) error {
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("Do you want to generate content? (y/N): ")
-	response, _ := reader.ReadString('\n')
-	response = strings.TrimSpace(strings.ToLower(response))
+	if opts.demo {
+		return generateDemoContent(projectPath)
+	}
 
-	if response != "y" {
+	generate := opts.generateContent.value
+	if !opts.generateContent.set && opts.interactive {
+		notifyWaitingForInput("Choose whether to generate sample content")
+		generate = strings.ToLower(promptLine("Do you want to generate content? (y/N): ")) == "y"
+	}
+
+	recordAnswer("generate-content", strconv.FormatBool(generate))
+	if !generate {
 		printSuccess("✓ Drupal content generation skipped")
 		return nil
 	}
 
+	return generateSampleContent(projectPath, 10, 25)
+}
+
+func generateSampleContent(projectPath string, users, nodes int) error {
 	printStatus("Generating Drupal content...")
 
-	cmd := exec.Command("ddev", "drush", "genu", "10", "--kill", "--roles=content_editor")
-	cmd.Dir = projectPath
+	cmd := ddevCommand(projectPath, "drush", "genu", strconv.Itoa(users), "--kill", "--roles=content_editor")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := runCmd(cmd); err != nil {
 		printError("Failed to generate users")
 		return err
 	}
 
-	cmd = exec.Command("ddev", "drush", "genc", "25", "-y", "--kill", "--roles=content_editor", "--skip-fields=field_tags")
-	cmd.Dir = projectPath
+	cmd = ddevCommand(projectPath, "drush", "genc", strconv.Itoa(nodes), "-y", "--kill", "--roles=content_editor", "--skip-fields=field_tags")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
+	if err := runCmd(cmd); err != nil {
 		printError("Failed to generate content")
 		return err
 	}
@@ -451,7 +591,10 @@ func getSiteURL(projectPath string) string {
 
 	cmd := exec.Command("ddev", "describe", "--json-output")
 	cmd.Dir = projectPath
-	output, err := cmd.Output()
+	if dryRunCommand(cmd) {
+		return ""
+	}
+	output, err := commandOutput(cmd)
 	if err != nil {
 		printWarning("Could not determine site URL. Try running 'ddev describe'")
 		return ""
@@ -476,7 +619,7 @@ func getSiteURL(projectPath string) string {
 	return ""
 }
 
-func displayFinalInstructions(siteURL string) {
+func displayFinalInstructions(projectPath, siteURL string) {
 	fmt.Println()
 	fmt.Println("==========================================")
 	printSuccess("Drupal 11 installation completed!")
@@ -488,7 +631,19 @@ func displayFinalInstructions(siteURL string) {
 	} else {
 		fmt.Println("1. Run 'ddev describe' to get your site URL")
 	}
-	fmt.Println("2. Login with: username=admin, password=admin")
+	fmt.Printf("2. Login with: username=admin, password=%s\n", opts.adminPassword)
+	if siteBasicAuth != nil {
+		fmt.Printf("   HTTP basic auth: username=%s, password=%s\n", siteBasicAuth.Username, siteBasicAuth.Password)
+	}
+	if tool, _ := databaseUITool(); tool != "" {
+		if u := databaseUIURL(projectPath, siteURL, tool); u != "" {
+			fmt.Printf("   Database (%s): %s  (or 'ddev %s')\n", tool, u, tool)
+		}
+	}
+	if opts.demo {
+		printDemoTour(siteURL)
+		fmt.Println()
+	}
 	fmt.Println("3. Useful DDEV commands:")
 	fmt.Println("   - ddev describe    # Show project info")
 	fmt.Println("   - ddev drush       # Run Drush commands")
@@ -500,14 +655,34 @@ func displayFinalInstructions(siteURL string) {
 }
 
 func selectDockerProvider() string {
-	reader := bufio.NewReader(os.Stdin)
+	if opts.dockerProvider != "" {
+		return opts.dockerProvider
+	}
+
+	if defaults.DockerProvider != "" {
+		printStatus(fmt.Sprintf("Using preferred Docker provider from config.yml: %s", providerLabel(defaults.DockerProvider)))
+		return defaults.DockerProvider
+	}
+
+	rec := recommendProvider(detectHost())
+	if !opts.interactive {
+		printStatus(fmt.Sprintf("Using recommended Docker provider: %s (%s)", providerLabel(rec.provider), rec.reason))
+		return rec.provider
+	}
+
+	defaultChoice := "1"
+	if rec.provider == "colima" {
+		defaultChoice = "2"
+	}
+
 	fmt.Println("Which Docker provider would you like to use?")
 	fmt.Println("1. Docker Desktop")
 	fmt.Println("2. Colima")
-	fmt.Print("Enter your choice (1 or 2): ")
-
-	response, _ := reader.ReadString('\n')
-	response = strings.TrimSpace(response)
+	fmt.Printf("Recommended: %s (%s)\n", providerLabel(rec.provider), rec.reason)
+	response := promptLine(fmt.Sprintf("Enter your choice (1 or 2) [%s]: ", defaultChoice))
+	if response == "" {
+		response = defaultChoice
+	}
 
 	if response == "2" {
 		return "colima"
@@ -515,19 +690,391 @@ func selectDockerProvider() string {
 	return "docker"
 }
 
+func runSubcommand(name string, args []string) int {
+	switch name {
+	case "install":
+		return runInstall(args)
+	case "preflight":
+		return runPreflight(args)
+	case "ddev-init":
+		return runDDEVInit(args)
+	case "modules", "enable-modules":
+		return runModules(args)
+	case "import-config":
+		return runImportConfig(args)
+	case "destroy":
+		return runDestroy(args)
+	case "scaffold":
+		return runScaffold(args)
+	case "watch":
+		return runWatch(args)
+	case "benchmark":
+		return runBenchmark(args)
+	case "provider":
+		return runProvider(args)
+	case "workshop":
+		return runWorkshop(args)
+	case "verify":
+		return runVerify(args)
+	case "status":
+		return runStatus(args)
+	case "convert":
+		return runConvert(args)
+	case "env":
+		return runEnv(args)
+	case "pin":
+		return runPin(args)
+	case "verify-lock":
+		return runVerifyLock(args)
+	case "licenses":
+		return runLicenses(args)
+	case "sbom":
+		return runSBOM(args)
+	case "docs":
+		return runDocs(args)
+	case "serve":
+		return runServe(args)
+	case "preview":
+		return runPreview(args)
+	case "diff-projects":
+		return runDiffProjects(args)
+	case "config-check":
+		return runConfigCheck(args)
+	case "tunnel":
+		return runTunnel(args)
+	case "remote":
+		return runRemote(args)
+	case "maintenance":
+		return runMaintenance(args)
+	case "release":
+		return runRelease(args)
+	case "predeploy-check":
+		return runPredeployCheck(args)
+	case "init":
+		return runInit(args)
+	case "content":
+		return runContent(args)
+	case "db":
+		return runDB(args)
+	case "lock":
+		return runLock(args)
+	case "archive":
+		return runArchive(args)
+	case "revive":
+		return runRevive(args)
+	}
+	printError(fmt.Sprintf("Unknown command %q", name))
+	return 2
+}
+
 func main() {
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		os.Exit(runSubcommand(os.Args[1], withoutNoColorFlag(os.Args[2:])))
+	}
+	os.Exit(runInstall(os.Args[1:]))
+}
+
+func runInstall(args []string) int {
+	if err := parseFlags(args); err != nil {
+		return 2
+	}
 	fmt.Println("==========================================")
 	fmt.Println("Drupal 11 Installation Script")
 	fmt.Println("==========================================")
 	fmt.Println()
 
+	if opts.resume != "" {
+		if err := prepareResume(args); err != nil {
+			return 2
+		}
+	} else {
+		startCheckpoint(args)
+	}
+	if err := openLogFile(); err != nil {
+		return 2
+	}
+	defer closeLogFile()
+
+	if err := loadProjectConfig(opts.configFile); err != nil {
+		return 1
+	}
+	if err := applyInstallSettings(); err != nil {
+		printError(err.Error())
+		return 2
+	}
+	if err := requireNonInteractiveFlags(); err != nil {
+		return 1
+	}
+	if err := loadSharedServer(); err != nil {
+		return 1
+	}
+	if err := loadUserDefaults(); err != nil {
+		printError(err.Error())
+		return 2
+	}
+	if _, err := constraintPolicy(); err != nil {
+		printError(err.Error())
+		return 2
+	}
+	if _, err := cronInterval(); err != nil {
+		printError(err.Error())
+		return 2
+	}
+	if _, err := databaseUITool(); err != nil {
+		printError(err.Error())
+		return 2
+	}
+	if _, err := selectedIDEs(); err != nil {
+		printError(err.Error())
+		return 2
+	}
+	if err := loadBranding(); err != nil {
+		printError(err.Error())
+		return 2
+	}
+	if _, err := scaffoldMappings(project.Scaffold, projectConfigDir); err != nil {
+		printError(err.Error())
+		return 2
+	}
+	if opts.existingConfig != "" {
+		if _, err := os.Stat(filepath.Join(opts.existingConfig, "system.site.yml")); err != nil {
+			printError(fmt.Sprintf("--existing-config %s is not a full config export (no system.site.yml)", opts.existingConfig))
+			return 2
+		}
+	}
+	if err := loadLockSnapshot(); err != nil {
+		printError(err.Error())
+		return 2
+	}
+
+	if opts.quick {
+		printStatus("Quick mode: skipping dev packages, seed configuration and content generation")
+		if opts.generateContent.value {
+			printWarning("--generate-content is ignored in quick mode")
+		}
+		for _, module := range quickModeSkippedModules {
+			removeFromInstall(module)
+		}
+		fmt.Println()
+	}
+
+	presets := opts.presets
+	if !opts.quick {
+		presets = append(defaultPresets, presets...)
+	}
+	if opts.demo {
+		if opts.quick {
+			printError("--demo and --quick cannot be combined")
+			return 2
+		}
+		printStatus("Demo mode: Umami profile, generated demo content and all compatible presets")
+		presets = compatiblePresets(append(presets, presetNames()...))
+	}
+	if opts.harden {
+		presets = append(presets, "harden")
+	}
+	if err := activatePresets(presets); err != nil {
+		return 2
+	}
+
+	if opts.policyURL != "" {
+		policy, err := fetchOrgPolicy(opts.policyURL)
+		if err != nil {
+			return 1
+		}
+		activePolicy = policy
+		applyOrgPolicy(policy)
+		fmt.Println()
+	}
+
+	if opts.dryRun {
+		printDryRun("Nothing will be installed or changed; commands and file changes are printed instead")
+		fmt.Println()
+	}
+	started := time.Now()
+	err := runInstallPipeline()
+	writeAnswers()
+	if err != nil {
+		emitEvent(event{Event: "finished", Status: "failed", Error: err.Error(), Duration: time.Since(started).Seconds()})
+		notifyPipelineResult(err, time.Since(started))
+		return 1
+	}
+	emitEvent(event{Event: "finished", Status: "ok", Duration: time.Since(started).Seconds()})
+	notifyPipelineResult(nil, time.Since(started))
+	return 0
+}
+
+func runInstallPipeline() error {
+	drushAutoInstall = true
 	dockerProvider := selectDockerProvider()
+	recordAnswer("docker-provider", dockerProvider)
+	fmt.Println()
+	if err := resolveLocale(); err != nil {
+		printError(err.Error())
+		return err
+	}
+	recordAnswer("timezone", siteLocale.timezone)
+	if siteLocale.country != "" {
+		recordAnswer("country", siteLocale.country)
+	}
+	recordAnswer("first-day", weekdays[siteLocale.firstDay])
 	fmt.Println()
 
 	checkPrerequisites(dockerProvider)
 
+	var projectPath string
+	if checkpoint.done("create-project") {
+		projectPath = checkpoint.project
+		if err := restoreSkippedState(projectPath); err != nil {
+			printError(fmt.Sprintf("Failed to restore the install state: %v", err))
+			return err
+		}
+	}
+	steps := []pipelineStep{
+		{name: "prerequisites", title: "Preparing Homebrew, Docker provider and DDEV", run: func() error {
+			return ensurePrerequisites(dockerProvider)
+		}},
+		{name: "create-project", title: "Creating Drupal project", run: func() error {
+			path, err := initDrupalProject()
+			if err != nil {
+				return err
+			}
+			projectPath = path
+			checkpoint.setProject(projectPath)
+			if err := configureGitAuthor(projectPath); err != nil {
+				printWarning(fmt.Sprintf("Failed to configure the git author: %v", err))
+			}
+			if lockSnapshot != "" {
+				printStatus("Leaving composer.json as saved in the lock snapshot")
+				return nil
+			}
+			if err := applyComposerPatch(projectPath, project.Composer); err != nil {
+				return err
+			}
+			if err := applyPresetComposerScripts(projectPath); err != nil {
+				return err
+			}
+			return applyScaffoldMappings(projectPath, projectConfigDir, project.Scaffold)
+		}},
+		{name: "ddev-config", title: "Configuring DDEV", run: func() error {
+			return configureDDEVProject(projectPath)
+		}},
+		{name: "editor-settings", title: "Writing editor and IDE settings", run: func() error {
+			if err := writeEditorSettings(projectPath); err != nil {
+				printError(fmt.Sprintf("Failed to write editor settings: %v", err))
+				return err
+			}
+			return nil
+		}},
+		{name: "ddev-start", title: "Starting DDEV", run: func() error {
+			return startDDEV(projectPath)
+		}},
+		{name: "dependencies", title: "Installing Composer dependencies", run: func() error {
+			return installDrupalDependencies(projectPath)
+		}},
+		{name: "settings", title: "Configuring Drupal settings", run: func() error {
+			return setupDrupalSettings(projectPath)
+		}},
+		{name: "site-install", title: "Installing Drupal site", run: func() error {
+			if err := installDrupalSite(projectPath); err != nil {
+				return err
+			}
+			return initDotEnv(projectPath, "local")
+		}},
+		{name: "modules", title: "Enabling modules", run: func() error {
+			if skipForExistingConfig("module installation") {
+				return nil
+			}
+			return enableDrupalModules(projectPath)
+		}},
+	}
+	if len(activePresets) > 0 {
+		steps = append(steps, pipelineStep{name: "presets", title: "Applying presets", run: func() error {
+			if skipForExistingConfig("presets") {
+				return nil
+			}
+			return applyPresets(projectPath)
+		}})
+	}
+	if !siteBranding.empty() {
+		steps = append(steps, pipelineStep{name: "branding", title: "Applying site branding", run: func() error {
+			if skipForExistingConfig("branding") {
+				return nil
+			}
+			return applyBranding(projectPath)
+		}})
+	}
+	if !opts.quick {
+		steps = append(steps, pipelineStep{name: "config-import", title: "Importing configuration", run: func() error {
+			if existingConfig {
+				return nil
+			}
+			return importDrupalConfig(projectPath)
+		}})
+	}
+	if containsString(drupalModules, "config_ignore") {
+		steps = append(steps, pipelineStep{name: "config-ignore", title: "Configuring config_ignore", run: func() error {
+			if skipForExistingConfig("config_ignore rules") {
+				return nil
+			}
+			return configureConfigIgnore(projectPath)
+		}})
+	}
+	if activePolicy != nil {
+		steps = append(steps, pipelineStep{name: "policy", title: "Validating organization policy", run: func() error {
+			return validateOrgPolicy(projectPath, activePolicy)
+		}})
+	}
+	if !opts.quick {
+		steps = append(steps, pipelineStep{name: "content", title: "Generating content", run: func() error {
+			if err := generateDrupalContent(projectPath); err != nil {
+				printWarning("Content generation failed, but continuing...")
+			}
+			return nil
+		}})
+	}
+
+	var siteURL string
+	steps = append(steps, pipelineStep{name: "verify", title: "Verifying installation", run: func() error {
+		siteURL = getSiteURL(projectPath)
+		if sharedMode() {
+			siteURL = "https://" + sharedHostname(projectPath)
+		}
+		if siteURL != "" {
+			emitEvent(event{Event: "site_url", URL: siteURL})
+		}
+		modules := drupalModules
+		if existingConfig || opts.skipModules {
+			modules = nil
+		}
+		registerProject(projectPath, siteURL, modules)
+		if err := runDDEVQuiet(projectPath, "drush", "cron"); err != nil {
+			printWarning("Failed to run cron")
+		}
+		return verifySite(projectPath, siteURL, modules)
+	}})
+	steps = append(steps, pipelineStep{name: "docs", title: "Writing project runbooks", run: func() error {
+		return writeRunbooks(projectPath)
+	}})
+
+	steps = withoutSkippedSteps(steps)
+	if err := runSteps(steps); err != nil {
+		return err
+	}
+	checkpoint.finish()
+	if opts.dryRun {
+		fmt.Println()
+		printSuccess("Dry run finished; nothing was installed or changed")
+		return nil
+	}
+
+	displayFinalInstructions(projectPath, siteURL)
+	return nil
+}
+
+func ensurePrerequisites(dockerProvider string) error {
 	if err := checkHomebrew(); err != nil {
-		os.Exit(1)
+		return err
 	}
 
 	fmt.Println()
@@ -536,7 +1083,7 @@ func main() {
 		installDocker()
 		if !checkDockerRunning() {
 			printError("Please start Docker Desktop and run this script again.")
-			os.Exit(1)
+			return fmt.Errorf("docker is not running")
 		}
 	} else {
 		installColima()
@@ -544,54 +1091,53 @@ func main() {
 			startColima()
 			if !checkColimaRunning() {
 				printError("Failed to start Colima. Please start it manually and run this script again.")
-				os.Exit(1)
+				return fmt.Errorf("colima is not running")
 			}
 		}
 	}
 
 	if !installDDEV() {
-		os.Exit(1)
+		return fmt.Errorf("ddev is not installed")
 	}
+	applyDDEVTelemetry(ddevTelemetry)
 
-	checkDDEVVersion()
-
-	projectPath, err := initDrupalProject()
-	if err != nil {
-		os.Exit(1)
-	}
+	return checkDDEVVersion()
+}
 
+func configureDDEVProject(projectPath string) error {
 	if err := initDDEVProject(projectPath); err != nil {
-		os.Exit(1)
+		return err
 	}
 
-	if err := startDDEV(projectPath); err != nil {
-		os.Exit(1)
+	if err := installDDEVCommands(projectPath); err != nil {
+		return err
 	}
 
-	if err := installDrupalDependencies(projectPath); err != nil {
-		os.Exit(1)
+	if sharedMode() {
+		project.DDEV.AdditionalFQDNs = append(project.DDEV.AdditionalFQDNs, sharedHostname(projectPath))
+		warnSharedRouter()
 	}
-
-	if err := setupDrupalSettings(projectPath); err != nil {
-		os.Exit(1)
+	if err := writeDDEVOverrides(projectPath, project.DDEV); err != nil {
+		return err
 	}
 
-	if err := installDrupalSite(projectPath); err != nil {
-		os.Exit(1)
+	if err := writePHPIni(projectPath, project.PHP); err != nil {
+		return err
 	}
 
-	if err := enableDrupalModules(projectPath); err != nil {
-		os.Exit(1)
+	if err := writeWebserverConfig(projectPath, project.Webserver); err != nil {
+		return err
 	}
 
-	if err := importDrupalConfig(projectPath); err != nil {
-		os.Exit(1)
+	if err := installDatabaseUI(projectPath); err != nil {
+		return err
 	}
 
-	if err := generateDrupalContent(projectPath); err != nil {
-		printWarning("Content generation failed, but continuing...")
+	if opts.basicAuth {
+		project.BasicAuth.Enabled = true
 	}
-
-	siteURL := getSiteURL(projectPath)
-	displayFinalInstructions(siteURL)
+	if opts.basicAuthUser != "" {
+		project.BasicAuth.Username = opts.basicAuthUser
+	}
+	return setupBasicAuth(projectPath, project.BasicAuth)
 }
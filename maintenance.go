@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+func runMaintenance(args []string) int {
+	if len(args) == 0 || (args[0] != "on" && args[0] != "off" && args[0] != "status") {
+		printError("Usage: install-drupal maintenance <on|off|status> [--env <name|@alias|selected>] [--yes]")
+		return 2
+	}
+	action := args[0]
+	fs := flag.NewFlagSet("maintenance "+action, flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the local project")
+	env := fs.String("env", "", "Environment or drush alias ('selected' for the one chosen with 'env use'; default: the local site)")
+	yes := fs.Bool("yes", false, "Change a remote site without asking")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	opts.interactive = stdinIsTerminal()
+	alias, err := envFlagAlias(projectPath, *env)
+	if err != nil {
+		printError(err.Error())
+		return 2
+	}
+	site := contentSiteLabel(alias)
+
+	if action == "status" {
+		output, err := ddevOutput(projectPath, drushTarget(alias, "state:get", "system.maintenance_mode", "--format=string")...)
+		if err != nil {
+			printError(fmt.Sprintf("Could not read maintenance mode on %s: %v", site, err))
+			return 1
+		}
+		if value := strings.TrimSpace(string(output)); value == "1" || value == "true" {
+			printWarning(fmt.Sprintf("%s is in maintenance mode", site))
+		} else {
+			printSuccess(fmt.Sprintf("✓ %s is online", site))
+		}
+		return 0
+	}
+
+	value, verb := "1", "Putting %s into maintenance mode"
+	if action == "off" {
+		value, verb = "0", "Taking %s out of maintenance mode"
+	}
+	if alias != "" && alias != "@self" {
+		if !confirm(fmt.Sprintf("Turn maintenance mode %s on %s?", action, site), *yes) {
+			return 1
+		}
+	}
+	printStatus(fmt.Sprintf(verb, site))
+	if err := runDDEVQuiet(projectPath, drushTarget(alias, "state:set", "system.maintenance_mode", value, "--input-format=integer")...); err != nil {
+		printError(fmt.Sprintf("drush state:set failed on %s", site))
+		return 1
+	}
+	if err := runDDEVQuiet(projectPath, drushTarget(alias, "cache:rebuild")...); err != nil {
+		printWarning(fmt.Sprintf("Maintenance mode is %s, but the cache rebuild on %s failed", action, site))
+		return 1
+	}
+	printSuccess(fmt.Sprintf("✓ Maintenance mode %s on %s (caches rebuilt)", action, site))
+	return 0
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+type nixTemplateData struct {
+	Name       string
+	PHPVersion string
+	NixPHP     string
+}
+
+var nixFiles = map[string][][2]string{
+	"flake": {
+		{"nix-flake.nix", "flake.nix"},
+	},
+	"devenv": {
+		{"nix-devenv.nix", "devenv.nix"},
+		{"nix-devenv.yaml", "devenv.yaml"},
+	},
+}
+
+func runScaffoldNix(args []string) int {
+	fs := flag.NewFlagSet("scaffold nix", flag.ContinueOnError)
+	format := fs.String("format", "flake", "flake (nix develop) or devenv (devenv shell)")
+	path := fs.String("path", ".", "Directory inside the project")
+	envrc := fs.Bool("envrc", false, "Also write an .envrc that loads the shell with direnv")
+	force := fs.Bool("force", false, "Overwrite existing files")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	files, ok := nixFiles[*format]
+	if !ok {
+		printError(fmt.Sprintf("Unknown --format %q (expected flake or devenv)", *format))
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	version := projectPHPVersion(projectPath)
+	data := nixTemplateData{
+		Name:       filepath.Base(projectPath),
+		PHPVersion: version,
+		NixPHP:     "php" + strings.ReplaceAll(version, ".", ""),
+	}
+	for _, f := range files {
+		content, err := renderScaffold(f[0], data)
+		if err != nil {
+			printError(fmt.Sprintf("Failed to render %s: %v", f[0], err))
+			return 1
+		}
+		if err := writeScaffoldFile(filepath.Join(projectPath, f[1]), content, *force); err != nil {
+			return 1
+		}
+	}
+	if *envrc {
+		use := "use flake\n"
+		if *format == "devenv" {
+			use = "use devenv\n"
+		}
+		if err := writeScaffoldFile(filepath.Join(projectPath, ".envrc"), []byte(use), *force); err != nil {
+			return 1
+		}
+		for _, ignored := range []string{".direnv", ".devenv"} {
+			if err := ensureGitignored(projectPath, ignored); err != nil {
+				printWarning(fmt.Sprintf("Could not add %s to .gitignore: %v", ignored, err))
+			}
+		}
+	}
+
+	fmt.Println()
+	if *format == "flake" {
+		fmt.Printf("Run 'nix develop' for PHP %s, Composer and the project's drush (vendor/bin) on the host.\n", version)
+		fmt.Println("Commit flake.lock after the first run so everyone gets the same packages.")
+	} else {
+		fmt.Printf("Run 'devenv shell' for PHP %s, Composer and the project's drush (vendor/bin) on the host.\n", version)
+		fmt.Println("Commit devenv.lock after the first run so everyone gets the same packages.")
+	}
+	return 0
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+func sendDesktopNotification(title, message string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(message), strconv.Quote(title))
+		cmd = exec.Command("osascript", "-e", script)
+	case "linux":
+		if !commandExists("notify-send") {
+			return
+		}
+		cmd = exec.Command("notify-send", "--app-name=drupal-scripts", title, message)
+	default:
+		return
+	}
+	_ = cmd.Run()
+}
+
+func notifyPipelineResult(err error, elapsed time.Duration) {
+	if opts.dryRun {
+		return
+	}
+	elapsed = elapsed.Round(time.Second)
+	title, message := "Drupal install complete", fmt.Sprintf("Finished in %s", elapsed)
+	if err != nil {
+		title, message = "Drupal install failed", fmt.Sprintf("Stopped after %s: %v", elapsed, err)
+	}
+	if opts.notify {
+		sendDesktopNotification(title, message)
+	}
+	sendWebhooks("install", title+": "+opts.projectName, message)
+}
+
+func notifyWaitingForInput(message string) {
+	if opts.notify {
+		sendDesktopNotification("Drupal install needs input", message)
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	colorRed    = "\033[0;31m"
+	colorGreen  = "\033[0;32m"
+	colorYellow = "\033[1;33m"
+	colorBlue   = "\033[0;34m"
+	colorReset  = "\033[0m"
+)
+
+var (
+	useColor  = os.Getenv("NO_COLOR") == "" && isTerminal(os.Stdout)
+	asciiOnly = false
+)
+
+func withoutNoColorFlag(args []string) []string {
+	var kept []string
+	for i, arg := range args {
+		if arg == "--" || (i > 0 && !strings.HasPrefix(arg, "-") && !maybeFlagValue(args[i-1])) {
+			return append(kept, args[i:]...)
+		}
+		if arg == "--no-color" || arg == "-no-color" {
+			useColor = false
+			continue
+		}
+		kept = append(kept, arg)
+	}
+	return kept
+}
+
+func maybeFlagValue(prev string) bool {
+	return strings.HasPrefix(prev, "-") && prev != "--" && !strings.Contains(prev, "=")
+}
+
+var asciiReplacer = strings.NewReplacer("✓", "[OK]", "✗", "[X]")
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	if devNull, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, devNull) {
+		return false
+	}
+	return true
+}
+
+func printLabeled(color, label, msg string) {
+	if asciiOnly {
+		msg = asciiReplacer.Replace(msg)
+	}
+	logLine(label, msg)
+	if jsonOutput() {
+		emitEvent(event{Event: "message", Level: strings.ToLower(label), Message: msg})
+		return
+	}
+	if !useColor {
+		fmt.Printf("[%s] %s\n", label, msg)
+		return
+	}
+	fmt.Printf("%s[%s]%s %s\n", color, label, colorReset, msg)
+}
+
+func printStatus(msg string) {
+	if opts.quiet {
+		logLine("INFO", msg)
+		return
+	}
+	printLabeled(colorBlue, "INFO", msg)
+}
+
+func printSuccess(msg string) {
+	if opts.quiet {
+		logLine("SUCCESS", msg)
+		return
+	}
+	printLabeled(colorGreen, "SUCCESS", msg)
+}
+
+func printStep(msg string) {
+	printLabeled(colorBlue, "INFO", msg)
+}
+
+func printWarning(msg string) {
+	printLabeled(colorYellow, "WARNING", msg)
+}
+
+func printError(msg string) {
+	printLabeled(colorRed, "ERROR", msg)
+}
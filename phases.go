@@ -0,0 +1,279 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func skipFlags() map[string][]string {
+	flags := map[string][]string{}
+	if opts.skipDeps {
+		flags["--skip-deps"] = []string{"dependencies"}
+	}
+	if opts.skipModules {
+		flags["--skip-modules"] = []string{"modules", "presets", "config-ignore"}
+	}
+	if opts.skipConfigImport {
+		flags["--skip-config-import"] = []string{"config-import"}
+	}
+	if opts.skipContent {
+		flags["--skip-content"] = []string{"content"}
+	}
+	return flags
+}
+
+func withoutSkippedSteps(steps []pipelineStep) []pipelineStep {
+	skipped := map[string]string{}
+	flags := skipFlags()
+	for _, flag := range sortedKeys(flags) {
+		for _, name := range flags[flag] {
+			skipped[name] = flag
+		}
+	}
+	var kept []pipelineStep
+	for _, step := range steps {
+		if flag, ok := skipped[step.name]; ok {
+			printStatus(fmt.Sprintf("Skipping %s (%s)", strings.ToLower(step.title[:1])+step.title[1:], flag))
+			continue
+		}
+		kept = append(kept, step)
+	}
+	return kept
+}
+
+func runPreflight(args []string) int {
+	fs := flag.NewFlagSet("preflight", flag.ContinueOnError)
+	fs.StringVar(&opts.dockerProvider, "docker-provider", "", "Docker provider to check: docker or colima (default: config.yml, else the recommendation)")
+	check := fs.Bool("check", false, "Only report what is missing; do not install or start anything")
+	fs.BoolVar(&opts.upgradeDDEV, "upgrade-ddev", false, "Upgrade DDEV without asking when it is older than the supported minimum")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	opts.interactive = stdinIsTerminal()
+	opts.dockerProvider = strings.ToLower(opts.dockerProvider)
+	if opts.dockerProvider != "" && opts.dockerProvider != "docker" && opts.dockerProvider != "colima" {
+		printError(fmt.Sprintf("Invalid --docker-provider %q (expected docker or colima)", opts.dockerProvider))
+		return 2
+	}
+	if err := loadUserDefaults(); err != nil {
+		printError(err.Error())
+		return 2
+	}
+	provider := selectDockerProvider()
+	fmt.Println()
+	checkPrerequisites(provider)
+
+	if *check {
+		var problems []string
+		if !commandExists("brew") {
+			problems = append(problems, "Homebrew is not installed")
+		}
+		if provider == "docker" && !checkDockerRunning() {
+			problems = append(problems, "Docker Desktop is not running")
+		}
+		if provider == "colima" && !checkColimaRunning() {
+			problems = append(problems, "Colima is not running")
+		}
+		if !commandExists("ddev") {
+			problems = append(problems, "DDEV is not installed")
+		} else if version := installedDDEVVersion(); version != "" && compareVersions(version, minDDEVVersion) < 0 {
+			problems = append(problems, fmt.Sprintf("DDEV %s is older than %s", version, minDDEVVersion))
+		}
+		for _, p := range problems {
+			printError("✗ " + p)
+		}
+		if len(problems) > 0 {
+			fmt.Println("Run 'install-drupal preflight' without --check to install and start what is missing.")
+			return 1
+		}
+		printSuccess("✓ Ready to install")
+		return 0
+	}
+
+	if err := ensurePrerequisites(provider); err != nil {
+		printError(err.Error())
+		return 1
+	}
+	fmt.Println()
+	printSuccess("✓ Homebrew, the Docker provider and DDEV are ready")
+	return 0
+}
+
+func runDDEVInit(args []string) int {
+	fs := flag.NewFlagSet("ddev-init", flag.ContinueOnError)
+	path := fs.String("path", ".", "Root of the Drupal Composer project")
+	fs.StringVar(&opts.docroot, "docroot", "", "Docroot for DDEV (default: detected)")
+	fs.StringVar(&opts.projectType, "project-type", "", "DDEV project type (default: detected from drupal/core)")
+	fs.StringVar(&opts.dbUI, "db-ui", "", "Install a database UI add-on: phpmyadmin or adminer")
+	fs.StringVar(&opts.cron, "cron", "", "How often DDEV runs drush cron, e.g. 15m, or off (default 10m)")
+	fs.BoolVar(&opts.basicAuth, "basic-auth", false, "Protect the site with HTTP basic auth")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if !isDrupalComposerProject(projectPath) {
+		printError(fmt.Sprintf("%s has no composer.json requiring drupal/core", projectPath))
+		return 1
+	}
+	if err := loadProjectConfigFrom(projectPath); err != nil {
+		return 1
+	}
+	if _, err := cronInterval(); err != nil {
+		printError(err.Error())
+		return 2
+	}
+	if _, err := databaseUITool(); err != nil {
+		printError(err.Error())
+		return 2
+	}
+
+	if err := configureDDEVProject(projectPath); err != nil {
+		return 1
+	}
+	fmt.Println()
+	printSuccess(fmt.Sprintf("✓ DDEV configured for %s; start it with 'ddev start'", filepath.Base(projectPath)))
+	return 0
+}
+
+func runImportConfig(args []string) int {
+	fs := flag.NewFlagSet("import-config", flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the project")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if err := importDrupalConfig(projectPath); err != nil {
+		return 1
+	}
+	return 0
+}
+
+func runModules(args []string) int {
+	fs := flag.NewFlagSet("modules", flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the project")
+	require := fs.Bool("require", false, "composer require drupal/<module> for the named modules first")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if err := loadProjectConfigFrom(projectPath); err != nil {
+		return 1
+	}
+	if err := loadUserDefaults(); err != nil {
+		printError(err.Error())
+		return 2
+	}
+
+	if fs.NArg() > 0 {
+		drupalModules = fs.Args()
+		if *require {
+			composer, err := readJSONFile(filepath.Join(projectPath, "composer.json"))
+			if err != nil {
+				printError(err.Error())
+				return 1
+			}
+			var packages []string
+			for _, module := range drupalModules {
+				if pkg := "drupal/" + module; !composerRequires(composer, pkg) {
+					packages = append(packages, pkg)
+				}
+			}
+			if len(packages) > 0 {
+				printStatus(fmt.Sprintf("Requiring %s", strings.Join(packages, ", ")))
+				if err := runDDEV(projectPath, append([]string{"composer", "require"}, packages...)...); err != nil {
+					printError("composer require failed")
+					return 1
+				}
+			}
+		}
+	} else if *require {
+		printError("--require needs the modules to require")
+		return 2
+	}
+
+	if err := enableDrupalModules(projectPath); err != nil {
+		return 1
+	}
+	return 0
+}
+
+func runContentGenerate(args []string) int {
+	fs := flag.NewFlagSet("content generate", flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the project")
+	users := fs.Int("users", 10, "Number of content editors to generate")
+	nodes := fs.Int("nodes", 25, "Number of nodes to generate")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if modules, err := enabledModulesAt(projectPath, ""); err == nil && !modules["devel_generate"] {
+		printError("devel_generate is not enabled; run 'install-drupal modules devel_generate' first")
+		return 1
+	}
+	if err := generateSampleContent(projectPath, *users, *nodes); err != nil {
+		return 1
+	}
+	return 0
+}
+
+func runDestroy(args []string) int {
+	fs := flag.NewFlagSet("destroy", flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the project")
+	keepFiles := fs.Bool("keep-files", false, "Remove the DDEV project and database but keep the project directory")
+	yes := fs.Bool("yes", false, "Destroy without asking for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	opts.interactive = stdinIsTerminal()
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	what := "DDEV containers and database"
+	if !*keepFiles {
+		what += " and every file in " + projectPath
+	}
+	printWarning(fmt.Sprintf("This deletes the %s, without a snapshot.", what))
+	if !confirm(fmt.Sprintf("Destroy %s?", filepath.Base(projectPath)), *yes) {
+		return 1
+	}
+
+	steps := []pipelineStep{
+		{name: "ddev-delete", title: "Removing the DDEV project", run: func() error {
+			return runDDEVQuiet(projectPath, "delete", "--omit-snapshot", "--yes")
+		}},
+	}
+	if !*keepFiles {
+		steps = append(steps, pipelineStep{name: "remove", title: "Removing the project directory", run: func() error {
+			if err := os.RemoveAll(projectPath); err != nil {
+				return err
+			}
+			return unregisterProject(projectPath)
+		}})
+	}
+	if err := runSteps(steps); err != nil {
+		return 1
+	}
+	printSuccess(fmt.Sprintf("✓ %s destroyed", filepath.Base(projectPath)))
+	return 0
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	constraintCaret = "caret"
+	constraintExact = "exact"
+)
+
+type composerLock struct {
+	ContentHash string          `json:"content-hash"`
+	Packages    []lockedPackage `json:"packages"`
+	PackagesDev []lockedPackage `json:"packages-dev"`
+}
+
+type lockedPackage struct {
+	Name      string     `json:"name"`
+	Version   string     `json:"version"`
+	Source    packageRef `json:"source"`
+	Dist      packageRef `json:"dist"`
+	Abandoned any        `json:"abandoned"`
+	License   []string   `json:"license"`
+}
+
+type packageRef struct {
+	Reference string `json:"reference"`
+}
+
+func loadComposerLock(projectPath string) (*composerLock, error) {
+	data, err := os.ReadFile(filepath.Join(projectPath, "composer.lock"))
+	if err != nil {
+		return nil, err
+	}
+	var lock composerLock
+	if err := json.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("composer.lock: %v", err)
+	}
+	return &lock, nil
+}
+
+type pinnedConstraint struct {
+	name, from, to string
+}
+
+func readComposerLock(projectPath string) (map[string]string, error) {
+	lock, err := loadComposerLock(projectPath)
+	if err != nil {
+		return nil, err
+	}
+	versions := map[string]string{}
+	for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+		versions[pkg.Name] = strings.TrimPrefix(pkg.Version, "v")
+	}
+	return versions, nil
+}
+
+func pinDrupalPackages(projectPath string, includeDev, write bool) (pinned []pinnedConstraint, skipped []string, err error) {
+	versions, err := readComposerLock(projectPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	composerPath := filepath.Join(projectPath, "composer.json")
+	composer, err := readJSONFile(composerPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sections := []string{"require"}
+	if includeDev {
+		sections = append(sections, "require-dev")
+	}
+	for _, section := range sections {
+		require, ok := composer.values[section].(*jsonObject)
+		if !ok {
+			continue
+		}
+		for _, name := range require.keys {
+			if !strings.HasPrefix(name, "drupal/") {
+				continue
+			}
+			version, ok := versions[name]
+			if !ok {
+				skipped = append(skipped, name+" (not in composer.lock)")
+				continue
+			}
+			if strings.HasPrefix(version, "dev-") || strings.HasSuffix(version, "-dev") {
+				skipped = append(skipped, name+" (development branch "+version+")")
+				continue
+			}
+			from, _ := require.values[name].(string)
+			if from == version {
+				continue
+			}
+			require.set(name, version)
+			pinned = append(pinned, pinnedConstraint{name: name, from: from, to: version})
+		}
+	}
+	sort.Slice(pinned, func(i, j int) bool { return pinned[i].name < pinned[j].name })
+
+	if write && len(pinned) > 0 {
+		if err := writeJSONFile(composerPath, composer); err != nil {
+			return nil, nil, err
+		}
+	}
+	return pinned, skipped, nil
+}
+
+func pinAndRelock(projectPath string, includeDev bool) error {
+	pinned, skipped, err := pinDrupalPackages(projectPath, includeDev, true)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to pin packages: %v", err))
+		return err
+	}
+	for _, p := range pinned {
+		printStatus(fmt.Sprintf("%s: %s -> %s", p.name, p.from, p.to))
+	}
+	for _, s := range skipped {
+		printWarning("Not pinned: " + s)
+	}
+	if len(pinned) == 0 {
+		printSuccess("✓ drupal/* packages are already pinned")
+		return nil
+	}
+	if err := runDDEVQuiet(projectPath, "composer", "update", "--lock"); err != nil {
+		printError("Failed to refresh composer.lock")
+		return err
+	}
+	printSuccess(fmt.Sprintf("✓ Pinned %d drupal/* packages to exact versions", len(pinned)))
+	return nil
+}
+
+func constraintPolicy() (string, error) {
+	policy := opts.constraints
+	if policy == "" {
+		policy = project.Composer.Constraints
+	}
+	switch policy {
+	case "", constraintCaret:
+		return constraintCaret, nil
+	case constraintExact:
+		return constraintExact, nil
+	}
+	return "", fmt.Errorf("invalid constraint policy %q (expected caret or exact)", policy)
+}
+
+func runPin(args []string) int {
+	fs := flag.NewFlagSet("pin", flag.ContinueOnError)
+	path := fs.String("path", ".", "Path to the Drupal project")
+	dev := fs.Bool("dev", false, "Also pin drupal/* packages in require-dev")
+	dryRun := fs.Bool("dry-run", false, "Show the new constraints without changing composer.json")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	if *dryRun {
+		pinned, skipped, err := pinDrupalPackages(projectPath, *dev, false)
+		if err != nil {
+			printError(fmt.Sprintf("Failed to read packages: %v", err))
+			return 1
+		}
+		for _, p := range pinned {
+			fmt.Printf("%s: %s -> %s\n", p.name, p.from, p.to)
+		}
+		for _, s := range skipped {
+			printWarning("Not pinned: " + s)
+		}
+		return 0
+	}
+
+	if err := pinAndRelock(projectPath, *dev); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+const stepHistorySize = 10
+
+type pipelineStep struct {
+	name  string
+	title string
+	run   func() error
+}
+
+type stepHistory map[string][]float64
+
+func stepHistoryPath() (string, error) {
+	dir, err := userConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "timings.json"), nil
+}
+
+func loadStepHistory() stepHistory {
+	history := stepHistory{}
+	path, err := stepHistoryPath()
+	if err != nil {
+		return history
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return history
+	}
+	if err := json.Unmarshal(data, &history); err != nil {
+		printWarning(fmt.Sprintf("Ignoring unreadable step timings in %s", path))
+		return stepHistory{}
+	}
+	return history
+}
+
+func (h stepHistory) save() {
+	path, err := stepHistoryPath()
+	if err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := writeFile(path, append(data, '\n')); err != nil {
+		printWarning(fmt.Sprintf("Could not save step timings: %v", err))
+	}
+}
+
+func (h stepHistory) record(step string, d time.Duration) {
+	samples := append(h[step], d.Seconds())
+	if len(samples) > stepHistorySize {
+		samples = samples[len(samples)-stepHistorySize:]
+	}
+	h[step] = samples
+}
+
+func (h stepHistory) estimate(step string) (time.Duration, bool) {
+	samples := h[step]
+	if len(samples) == 0 {
+		return 0, false
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+	return time.Duration(sorted[len(sorted)/2] * float64(time.Second)), true
+}
+
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+	return fmt.Sprintf("%dm%02ds", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+func printStepHeader(index, total int, step pipelineStep, remaining []pipelineStep, history stepHistory) {
+	msg := fmt.Sprintf("[%d/%d] %s", index+1, total, step.title)
+
+	var left time.Duration
+	known := true
+	for _, s := range remaining {
+		est, ok := history.estimate(s.name)
+		if !ok {
+			known = false
+			break
+		}
+		left += est
+	}
+	if est, ok := history.estimate(step.name); ok {
+		msg += fmt.Sprintf(" (usually ~%s", formatDuration(est))
+		if known {
+			msg += fmt.Sprintf(", ~%s remaining overall", formatDuration(left))
+		}
+		msg += ")"
+	}
+
+	if !opts.quiet {
+		fmt.Println()
+	}
+	printStep(msg)
+}
+
+type stepTiming struct {
+	step     pipelineStep
+	started  time.Time
+	finished time.Time
+	failed   bool
+}
+
+func printStepSummary(timings []stepTiming) {
+	if len(timings) == 0 {
+		return
+	}
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STEP\tSTARTED\tFINISHED\tDURATION\t")
+	for _, t := range timings {
+		status := ""
+		if t.failed {
+			status = "failed"
+			if opts.dryRun {
+				status = "stopped"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", t.step.title, t.started.Format(time.TimeOnly),
+			t.finished.Format(time.TimeOnly), formatDuration(t.finished.Sub(t.started)), status)
+	}
+	total := timings[len(timings)-1].finished.Sub(timings[0].started)
+	fmt.Fprintf(w, "Total\t\t\t%s\t\n", formatDuration(total))
+	w.Flush()
+}
+
+func runSteps(steps []pipelineStep) error {
+	history := loadStepHistory()
+	var timings []stepTiming
+	defer func() { printStepSummary(timings) }()
+
+	for i, step := range steps {
+		if checkpoint.done(step.name) {
+			printStep(fmt.Sprintf("[%d/%d] %s: completed before the install was interrupted, skipping", i+1, len(steps), step.title))
+			emitStepEvent("step_skipped", i, len(steps), step, 0, nil)
+			continue
+		}
+		printStepHeader(i, len(steps), step, steps[i:], history)
+		started := time.Now()
+		emitStepEvent("step_started", i, len(steps), step, 0, nil)
+		printStatus(fmt.Sprintf("Started %s at %s", step.name, started.Format(time.TimeOnly)))
+		err := step.run()
+		finished := time.Now()
+		timings = append(timings, stepTiming{step: step, started: started, finished: finished, failed: err != nil})
+		if err != nil && opts.dryRun {
+			printDryRun(fmt.Sprintf("Step %s stops here in a dry run: %v", step.name, err))
+			continue
+		}
+		if err != nil {
+			emitStepEvent("step_failed", i, len(steps), step, finished.Sub(started), err)
+			printError(fmt.Sprintf("Step %s failed at %s after %s", step.name, finished.Format(time.TimeOnly), formatDuration(finished.Sub(started))))
+			checkpoint.failed(step.name)
+			return err
+		}
+		emitStepEvent("step_finished", i, len(steps), step, finished.Sub(started), nil)
+		printStatus(fmt.Sprintf("Finished %s at %s (%s)", step.name, finished.Format(time.TimeOnly), formatDuration(finished.Sub(started))))
+		checkpoint.complete(step.name)
+		if opts.dryRun {
+			continue
+		}
+		history.record(step.name, finished.Sub(started))
+		history.save()
+	}
+	return nil
+}
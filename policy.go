@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type orgPolicy struct {
+	Name             string                       `yaml:"name" json:"name"`
+	RequiredPackages []string                     `yaml:"required_packages" json:"required_packages"`
+	RequiredModules  []string                     `yaml:"required_modules" json:"required_modules"`
+	BannedModules    []string                     `yaml:"banned_modules" json:"banned_modules"`
+	MinimumPHP       string                       `yaml:"minimum_php" json:"minimum_php"`
+	MandatoryConfig  map[string]map[string]string `yaml:"mandatory_config" json:"mandatory_config"`
+}
+
+var activePolicy *orgPolicy
+
+func fetchOrgPolicy(source string) (*orgPolicy, error) {
+	printStatus(fmt.Sprintf("Loading organization policy from %s", source))
+
+	var data []byte
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		data, err = httpGet(source)
+		if err == nil {
+			err = verifyDownload("organization policy", source, data, opts.policySHA256)
+		}
+	} else {
+		data, err = os.ReadFile(strings.TrimPrefix(source, "file://"))
+	}
+	if err != nil {
+		printError(fmt.Sprintf("Failed to fetch organization policy: %v", err))
+		return nil, err
+	}
+
+	policy := &orgPolicy{}
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		err = json.Unmarshal(data, policy)
+	} else {
+		err = yamlUnmarshal(data, policy)
+	}
+	if err != nil {
+		printError(fmt.Sprintf("Failed to parse organization policy: %v", err))
+		return nil, err
+	}
+
+	name := policy.Name
+	if name == "" {
+		name = source
+	}
+	printSuccess(fmt.Sprintf("Organization policy loaded: %s", name))
+	return policy, nil
+}
+
+func httpGet(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func packageName(pkg string) string {
+	name, _, _ := strings.Cut(pkg, ":")
+	return name
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func applyOrgPolicy(policy *orgPolicy) {
+	for _, pkg := range policy.RequiredPackages {
+		if !containsPackage(composerPackages, pkg) {
+			composerPackages = append(composerPackages, pkg)
+		}
+	}
+
+	for _, module := range policy.RequiredModules {
+		if !containsString(drupalModules, module) {
+			drupalModules = append(drupalModules, module)
+		}
+	}
+
+	for _, banned := range policy.BannedModules {
+		if removed := removeFromInstall(banned); len(removed) > 0 {
+			printWarning(fmt.Sprintf("Policy bans module '%s'; not installing %s", banned, strings.Join(removed, ", ")))
+		}
+	}
+}
+
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(strings.TrimSpace(a), "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(strings.TrimSpace(b), "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(strings.TrimLeft(leadingDigits(as[i]), "0"))
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(strings.TrimLeft(leadingDigits(bs[i]), "0"))
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func leadingDigits(s string) string {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	return s[:end]
+}
+
+func enabledModules(projectPath string) (map[string]bool, error) {
+	return enabledModulesAt(projectPath, "")
+}
+
+func enabledModulesAt(projectPath, alias string) (map[string]bool, error) {
+	output, err := ddevOutput(projectPath, drushTarget(alias, "pm:list", "--status=enabled", "--format=json")...)
+	if err != nil {
+		return nil, err
+	}
+	var list map[string]json.RawMessage
+	if err := json.Unmarshal(output, &list); err != nil {
+		return nil, err
+	}
+	enabled := make(map[string]bool, len(list))
+	for name := range list {
+		enabled[name] = true
+	}
+	return enabled, nil
+}
+
+func validateOrgPolicy(projectPath string, policy *orgPolicy) error {
+	printStatus("Validating project against organization policy...")
+
+	var violations []string
+
+	enabled, err := enabledModules(projectPath)
+	if err != nil {
+		violations = append(violations, "could not list enabled modules")
+	} else {
+		for _, module := range policy.RequiredModules {
+			if !enabled[module] {
+				violations = append(violations, fmt.Sprintf("required module '%s' is not enabled", module))
+			}
+		}
+		for _, module := range policy.BannedModules {
+			if enabled[module] {
+				violations = append(violations, fmt.Sprintf("banned module '%s' is enabled", module))
+			}
+		}
+	}
+
+	if policy.MinimumPHP != "" {
+		output, err := ddevOutput(projectPath, "exec", "php", "-r", "echo PHP_VERSION;")
+		phpVersion := strings.TrimSpace(string(output))
+		switch {
+		case err != nil:
+			violations = append(violations, "could not determine PHP version")
+		case compareVersions(phpVersion, policy.MinimumPHP) < 0:
+			violations = append(violations, fmt.Sprintf("PHP %s is older than the required minimum %s", phpVersion, policy.MinimumPHP))
+		}
+	}
+
+	configNames := make([]string, 0, len(policy.MandatoryConfig))
+	for name := range policy.MandatoryConfig {
+		configNames = append(configNames, name)
+	}
+	sort.Strings(configNames)
+	for _, name := range configNames {
+		for key, expected := range policy.MandatoryConfig[name] {
+			output, err := ddevOutput(projectPath, "drush", "config:get", name, key, "--format=json")
+			if err != nil {
+				violations = append(violations, fmt.Sprintf("config %s:%s is missing", name, key))
+				continue
+			}
+			var values map[string]any
+			if err := json.Unmarshal(output, &values); err != nil {
+				violations = append(violations, fmt.Sprintf("config %s:%s could not be read", name, key))
+				continue
+			}
+			for _, actual := range values {
+				if fmt.Sprint(actual) != expected {
+					violations = append(violations, fmt.Sprintf("config %s:%s is '%v', policy requires '%s'", name, key, actual, expected))
+				}
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		printError(fmt.Sprintf("Organization policy violations (%d):", len(violations)))
+		for _, v := range violations {
+			printError("✗ " + v)
+		}
+		return fmt.Errorf("%d policy violations", len(violations))
+	}
+
+	printSuccess("✓ Project complies with organization policy")
+	return nil
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+func pendingUpdates(projectPath string) ([]string, error) {
+	output, err := ddevOutput(projectPath, "drush", "updatedb:status", "--format=json")
+	if err != nil {
+		return nil, err
+	}
+	trimmed := strings.TrimSpace(string(output))
+	if trimmed == "" || trimmed == "[]" {
+		return nil, nil
+	}
+	var byID map[string]struct {
+		Module      string `json:"module"`
+		UpdateID    any    `json:"update_id"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(output, &byID); err != nil {
+		return nil, fmt.Errorf("could not parse drush updatedb:status output: %v", err)
+	}
+	var updates []string
+	for id, u := range byID {
+		updates = append(updates, fmt.Sprintf("%s: %s", firstNonEmpty(u.Module, id), strings.TrimSpace(u.Description)))
+	}
+	sort.Strings(updates)
+	return updates, nil
+}
+
+func restoreDatabase(projectPath, dump, alias string) error {
+	if dump != "" {
+		printStatus(fmt.Sprintf("Importing %s", dump))
+		return runDDEV(projectPath, "import-db", "--file="+dump)
+	}
+	printStatus(fmt.Sprintf("Copying the database from %s", alias))
+	return runDDEV(projectPath, "drush", "sql:sync", alias, "@self", "--yes")
+}
+
+func runPredeployCheck(args []string) int {
+	fs := flag.NewFlagSet("predeploy-check", flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the local project")
+	from := fs.String("from", "prod", "Environment or drush alias whose database is copied")
+	dump := fs.String("dump", "", "Use this database dump instead of copying from --from")
+	branch := fs.String("branch", "", "Release branch or tag to deploy (default: the current checkout)")
+	keep := fs.Bool("keep", false, "Leave the deployed database and checkout in place instead of restoring them")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	alias := ""
+	if *dump == "" {
+		if alias, err = resolveEnvironment(projectPath, *from); err != nil {
+			printError(err.Error())
+			return 2
+		}
+	} else if *dump, err = filepath.Abs(*dump); err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	original := ""
+	if *branch != "" {
+		if status, err := gitIn(projectPath, "status", "--porcelain", "--untracked-files=no"); err != nil {
+			printError(err.Error())
+			return 1
+		} else if status != "" {
+			printError("The working tree has uncommitted changes; commit or stash them before checking out another branch")
+			return 1
+		}
+		if original, err = gitIn(projectPath, "rev-parse", "--abbrev-ref", "HEAD"); err == nil && original == "HEAD" {
+			original, err = gitIn(projectPath, "rev-parse", "HEAD")
+		}
+		if err != nil {
+			printError(err.Error())
+			return 1
+		}
+	}
+
+	snapshot := "predeploy-" + time.Now().Format("20060102-150405")
+	printStatus(fmt.Sprintf("Saving the local database as snapshot %s", snapshot))
+	if err := runDDEVQuiet(projectPath, "snapshot", "--name="+snapshot); err != nil {
+		printError("Failed to snapshot the local database; nothing was changed")
+		return 1
+	}
+	if !*keep {
+		defer func() {
+			fmt.Println()
+			printStatus("Restoring the local checkout and database")
+			if original != "" {
+				if _, err := gitIn(projectPath, "checkout", "--quiet", original); err != nil {
+					printWarning(fmt.Sprintf("Could not check out %s again: %v", original, err))
+				} else if err := runDDEVQuiet(projectPath, "composer", "install"); err != nil {
+					printWarning("composer install failed after switching back; run it by hand")
+				}
+			}
+			if err := runDDEVQuiet(projectPath, "snapshot", "restore", snapshot); err != nil {
+				printWarning(fmt.Sprintf("Could not restore snapshot %s; run 'ddev snapshot restore %s'", snapshot, snapshot))
+				return
+			}
+			runDDEVQuiet(projectPath, "snapshot", "--cleanup", "--name="+snapshot, "--yes")
+		}()
+	}
+
+	var updates []string
+	var report []string
+	failed := false
+	steps := []pipelineStep{
+		{name: "restore", title: "Restoring the production database", run: func() error {
+			return restoreDatabase(projectPath, *dump, alias)
+		}},
+		{name: "checkout", title: "Checking out the release", run: func() error {
+			if *branch == "" {
+				return nil
+			}
+			if _, err := gitIn(projectPath, "checkout", "--quiet", *branch); err != nil {
+				return err
+			}
+			return runDDEV(projectPath, "composer", "install")
+		}},
+		{name: "preview", title: "Previewing updates and configuration", run: func() error {
+			var err error
+			if updates, err = pendingUpdates(projectPath); err != nil {
+				return fmt.Errorf("drush updatedb:status failed: %v", err)
+			}
+			for _, u := range updates {
+				fmt.Printf("  update %s\n", u)
+			}
+			if _, err := validateConfigImport(projectPath, "", false); err != nil {
+				report = append(report, "configuration check: "+err.Error())
+				failed = true
+			}
+			return nil
+		}},
+		{name: "deploy", title: "Running drush deploy", run: func() error {
+			if err := runDDEV(projectPath, "drush", "deploy", "--yes"); err != nil {
+				report = append(report, fmt.Sprintf("drush deploy failed: %v", err))
+				failed = true
+				return nil
+			}
+			states, err := configStates(projectPath, "")
+			if err != nil {
+				return err
+			}
+			for _, name := range sortedKeys(states) {
+				if states[name] != "Identical" {
+					report = append(report, fmt.Sprintf("config %s is still %q after deploy", name, states[name]))
+					failed = true
+				}
+			}
+			return nil
+		}},
+	}
+	if err := runSteps(steps); err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	fmt.Println()
+	printStatus(fmt.Sprintf("Deployment check: %d database updates, source %s", len(updates), firstNonEmpty(*dump, alias)))
+	if failed {
+		for _, line := range report {
+			printError("✗ " + line)
+		}
+		printError("The release would not deploy cleanly")
+		return 1
+	}
+	printSuccess("✓ drush deploy succeeded and the configuration matches config/sync")
+	return 0
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//go:embed presets
+var embeddedPresetConfig embed.FS
+
+type preset struct {
+	name             string
+	description      string
+	packages         []string
+	modules          []string
+	settings         []configSetting
+	ignoreConfig     []string
+	excludes         []string
+	composerScripts  []composerScript
+	scaffoldExcludes []string
+	tour             []tourStop
+	setup            func() error
+	apply            func(projectPath string) error
+}
+
+type configSetting struct {
+	name   string
+	values [][2]string
+}
+
+type tourStop struct {
+	path  string
+	label string
+}
+
+var presetRegistry = map[string]*preset{}
+
+var activePresets []*preset
+
+func registerPreset(p *preset) {
+	presetRegistry[p.name] = p
+}
+
+func presetNames() []string {
+	names := make([]string, 0, len(presetRegistry))
+	for name := range presetRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func presetActive(name string) bool {
+	for _, p := range activePresets {
+		if p.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func activatePresets(names []string) error {
+	for _, name := range names {
+		p, ok := presetRegistry[name]
+		if !ok {
+			printError(fmt.Sprintf("Unknown preset %q (available: %s)", name, strings.Join(presetNames(), ", ")))
+			return fmt.Errorf("unknown preset %s", name)
+		}
+		if presetActive(name) {
+			continue
+		}
+		for _, other := range p.excludes {
+			if presetActive(other) {
+				printError(fmt.Sprintf("Presets %s and %s cannot be combined", other, name))
+				return fmt.Errorf("conflicting presets %s and %s", other, name)
+			}
+		}
+		if p.setup != nil {
+			if err := p.setup(); err != nil {
+				printError(fmt.Sprintf("Preset %s: %v", p.name, err))
+				return err
+			}
+		}
+		activePresets = append(activePresets, p)
+
+		for _, pkg := range p.packages {
+			if !containsPackage(composerPackages, pkg) {
+				composerPackages = append(composerPackages, pkg)
+			}
+		}
+		for _, module := range p.modules {
+			if !containsString(drupalModules, module) {
+				drupalModules = append(drupalModules, module)
+			}
+		}
+		printStatus(fmt.Sprintf("Preset enabled: %s - %s", p.name, p.description))
+	}
+	return nil
+}
+
+func compatiblePresets(names []string) []string {
+	var kept []string
+	for _, name := range names {
+		p, ok := presetRegistry[name]
+		conflict := false
+		for _, other := range kept {
+			if ok && containsString(p.excludes, other) {
+				conflict = true
+			}
+		}
+		if !conflict && !containsString(kept, name) {
+			kept = append(kept, name)
+		}
+	}
+	return kept
+}
+
+func containsPackage(packages []string, pkg string) bool {
+	for _, existing := range packages {
+		if packageName(existing) == packageName(pkg) {
+			return true
+		}
+	}
+	return false
+}
+
+func applyPresets(projectPath string) error {
+	for _, p := range activePresets {
+		printStatus(fmt.Sprintf("Applying preset %s...", p.name))
+		if err := installPresetConfig(projectPath, p.name); err != nil {
+			printError(fmt.Sprintf("Preset %s failed", p.name))
+			return err
+		}
+		if err := applyConfigSettings(projectPath, p.settings); err != nil {
+			printError(fmt.Sprintf("Preset %s failed", p.name))
+			return err
+		}
+		if p.apply == nil {
+			continue
+		}
+		if err := p.apply(projectPath); err != nil {
+			printError(fmt.Sprintf("Preset %s failed", p.name))
+			return err
+		}
+	}
+	printSuccess("✓ Presets applied")
+	return nil
+}
+
+func installPresetConfig(projectPath, name string) error {
+	dir := "presets/" + name
+	entries, err := fs.ReadDir(embeddedPresetConfig, dir)
+	if err != nil {
+		return nil
+	}
+
+	syncPath := filepath.Join(projectPath, "config", "sync")
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yml") {
+			continue
+		}
+		data, err := fs.ReadFile(embeddedPresetConfig, dir+"/"+entry.Name())
+		if err != nil {
+			return err
+		}
+		if uuid := activeConfigUUID(projectPath, strings.TrimSuffix(entry.Name(), ".yml")); uuid != "" {
+			data = append([]byte("uuid: "+uuid+"\n"), data...)
+		}
+		if err := writeFile(filepath.Join(syncPath, entry.Name()), data); err != nil {
+			printError(fmt.Sprintf("Failed to write config %s", entry.Name()))
+			return err
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ".yml"))
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	if err := runDDEVQuiet(projectPath, "drush", "config:import", "--partial", "--yes"); err != nil {
+		printError("Failed to import preset configuration")
+		return err
+	}
+	return exportConfig(projectPath, names...)
+}
+
+func activeConfigUUID(projectPath, name string) string {
+	cmd := ddevCommand(projectPath, "drush", "config:get", name, "uuid", "--format=json")
+	if dryRunCommand(cmd) {
+		return ""
+	}
+	output, err := commandOutput(cmd)
+	if err != nil {
+		return ""
+	}
+	var values map[string]string
+	if err := json.Unmarshal(output, &values); err != nil {
+		return ""
+	}
+	return values[name+":uuid"]
+}
+
+func exportConfig(projectPath string, names ...string) error {
+	for _, name := range names {
+		output, err := ddevOutput(projectPath, "drush", "config:get", name)
+		if err != nil {
+			printError(fmt.Sprintf("Failed to export %s", name))
+			return err
+		}
+		if err := writeFile(filepath.Join(projectPath, "config", "sync", name+".yml"), output); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyConfigSettings(projectPath string, settings []configSetting) error {
+	for _, cfg := range settings {
+		for _, kv := range cfg.values {
+			if err := runDDEVQuiet(projectPath, "drush", "config:set", cfg.name, kv[0], kv[1], "--yes"); err != nil {
+				printError(fmt.Sprintf("Failed to set %s %s", cfg.name, kv[0]))
+				return err
+			}
+		}
+		if err := exportConfig(projectPath, cfg.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var previewNameReplacer = regexp.MustCompile(`[^a-z0-9]+`)
+
+func previewName(repo, branch string) string {
+	base := strings.TrimSuffix(filepath.Base(strings.TrimSuffix(repo, "/")), ".git")
+	name := strings.Trim(previewNameReplacer.ReplaceAllString(strings.ToLower(base+"-"+branch), "-"), "-")
+	if len(name) > 40 {
+		sum := sha1.Sum([]byte(repo + "#" + branch))
+		name = strings.Trim(name[:32], "-") + "-" + hex.EncodeToString(sum[:])[:7]
+	}
+	return name
+}
+
+func latestDump(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if !info.IsDir() {
+		return path, nil
+	}
+	var dumps []string
+	for _, pattern := range []string{"*.sql", "*.sql.gz", "*.sql.bz2", "*.sql.xz", "*.zip"} {
+		matches, _ := filepath.Glob(filepath.Join(path, pattern))
+		dumps = append(dumps, matches...)
+	}
+	if len(dumps) == 0 {
+		return "", fmt.Errorf("no database dumps in %s", path)
+	}
+	sort.Slice(dumps, func(i, j int) bool {
+		a, _ := os.Stat(dumps[i])
+		b, _ := os.Stat(dumps[j])
+		return a.ModTime().After(b.ModTime())
+	})
+	return dumps[0], nil
+}
+
+const previewMarker = "# Managed by drupal-scripts preview."
+
+func writePreviewConfig(projectPath, name, domain string) error {
+	content := previewMarker + "\nname: " + name + "\n"
+	if domain != "" {
+		content += "additional_fqdns:\n  - " + name + "." + domain + "\n"
+	}
+	return writeFile(filepath.Join(projectPath, ".ddev", "config.preview.yaml"), []byte(content))
+}
+
+func isPreview(projectPath, name string) bool {
+	data, err := os.ReadFile(filepath.Join(projectPath, ".ddev", "config.preview.yaml"))
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(string(data), previewMarker+"\nname: "+name+"\n")
+}
+
+func checkoutPreview(repo, branch, projectPath string) error {
+	if _, err := os.Stat(filepath.Join(projectPath, ".git")); err == nil {
+		printStatus(fmt.Sprintf("Updating %s to the latest %s...", projectPath, branch))
+		for _, args := range [][]string{
+			{"fetch", "--depth", "1", "origin", branch},
+			{"checkout", "-B", branch, "FETCH_HEAD"},
+		} {
+			cmd := exec.Command("git", args...)
+			cmd.Dir = projectPath
+			cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+			if err := cmd.Run(); err != nil {
+				return fmt.Errorf("git %s: %v", args[0], err)
+			}
+		}
+		return nil
+	}
+	printStatus(fmt.Sprintf("Cloning %s (%s)...", repo, branch))
+	return runCommand("git", "clone", "--depth", "1", "--branch", branch, repo, projectPath)
+}
+
+func runPreview(args []string) int {
+	fs := flag.NewFlagSet("preview", flag.ContinueOnError)
+	repo := fs.String("repo", "", "Git URL or path of the project repository (default: origin of the current directory)")
+	dir := fs.String("dir", ".", "Directory preview checkouts are created in")
+	db := fs.String("db", "", "Sanitized database dump to import, or a directory whose newest dump is used")
+	domain := fs.String("domain", "", "Also serve the preview at <name>.<domain> (wildcard DNS on a shared server)")
+	destroy := fs.Bool("destroy", false, "Delete the preview for the branch instead of creating it")
+	yes := fs.Bool("yes", false, "Delete with --destroy without asking")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		printError("Usage: install-drupal preview [flags] <branch>")
+		return 2
+	}
+	branch := fs.Arg(0)
+
+	if *repo == "" {
+		origin, err := runCommandOutput("git", "remote", "get-url", "origin")
+		if err != nil {
+			printError("No --repo given and the current directory has no git origin")
+			return 2
+		}
+		*repo = strings.TrimSpace(origin)
+	}
+	baseDir, err := filepath.Abs(*dir)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	name := previewName(*repo, branch)
+	projectPath := filepath.Join(baseDir, name)
+
+	if *destroy {
+		if !isPreview(projectPath, name) {
+			printError(fmt.Sprintf("%s is not a preview created by 'install-drupal preview'; not deleting it", projectPath))
+			return 1
+		}
+		opts.interactive = stdinIsTerminal()
+		if !confirm(fmt.Sprintf("Delete the preview %s, its checkout in %s and its database?", name, projectPath), *yes) {
+			return 1
+		}
+		if err := runDDEVQuiet(projectPath, "delete", "--omit-snapshot", "--yes"); err != nil {
+			printWarning(fmt.Sprintf("ddev delete failed: %v", err))
+		}
+		if err := os.RemoveAll(projectPath); err != nil {
+			printError(err.Error())
+			return 1
+		}
+		unregisterProject(projectPath)
+		printSuccess(fmt.Sprintf("✓ Preview %s removed", name))
+		return 0
+	}
+
+	dump := ""
+	if *db != "" {
+		if dump, err = latestDump(*db); err != nil {
+			printError(err.Error())
+			return 1
+		}
+	}
+
+	if err := checkoutPreview(*repo, branch, projectPath); err != nil {
+		printError(fmt.Sprintf("Failed to check out %s: %v", branch, err))
+		return 1
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, ".ddev", "config.yaml")); err != nil {
+		printError("The branch has no .ddev/config.yaml; previews need a committed DDEV configuration")
+		return 1
+	}
+	if err := writePreviewConfig(projectPath, name, *domain); err != nil {
+		printError(fmt.Sprintf("Failed to write preview DDEV config: %v", err))
+		return 1
+	}
+
+	steps := []pipelineStep{
+		{name: "ddev-start", title: "Starting DDEV", run: func() error { return runDDEVQuiet(projectPath, "start") }},
+		{name: "composer", title: "Installing Composer dependencies", run: func() error {
+			return runDDEVQuiet(projectPath, "composer", "install", "--no-interaction")
+		}},
+	}
+	if dump != "" {
+		steps = append(steps,
+			pipelineStep{name: "import-db", title: "Importing " + filepath.Base(dump), run: func() error {
+				return runDDEVQuiet(projectPath, "import-db", "--file="+dump)
+			}},
+			pipelineStep{name: "sanitize", title: "Sanitizing user data", run: func() error {
+				return runDDEVQuiet(projectPath, "drush", "sql:sanitize", "--yes")
+			}},
+		)
+	}
+	steps = append(steps,
+		pipelineStep{name: "deploy", title: "Running drush deploy", run: func() error {
+			return runDDEVQuiet(projectPath, "drush", "deploy", "--yes")
+		}},
+		pipelineStep{name: "verify", title: "Checking that the preview boots", run: func() error {
+			return checkBootstrap(projectPath)
+		}},
+	)
+	if err := runSteps(steps); err != nil {
+		return 1
+	}
+
+	siteURL := getSiteURL(projectPath)
+	if *domain != "" {
+		siteURL = "https://" + name + "." + *domain
+	}
+	registerProject(projectPath, siteURL, nil)
+	printSuccess(fmt.Sprintf("✓ Preview of %s is ready at %s", branch, siteURL))
+	sendWebhooks("preview", "Preview ready: "+branch, siteURL)
+	return 0
+}
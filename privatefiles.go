@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const privateFilesDir = "private"
+
+const privateFilesPerm os.FileMode = 0770
+
+func configurePrivateFiles(projectPath string) error {
+	dir := filepath.Join(projectPath, privateFilesDir)
+	if err := ensureDir(dir); err != nil {
+		printError("Failed to create the private files directory")
+		return err
+	}
+	if !opts.dryRun && runtime.GOOS != "windows" {
+		if err := os.Chmod(dir, privateFilesPerm); err != nil && !onWindowsMount(dir) {
+			return err
+		}
+	}
+
+	rel, err := filepath.Rel(filepath.Join(projectPath, projectDocroot(projectPath)), dir)
+	if err != nil {
+		return err
+	}
+	if err := editSettings(projectPath, func(f *settingsFile) (bool, error) {
+		return f.set("$settings['file_private_path']", phpString(filepath.ToSlash(rel))), nil
+	}); err != nil {
+		return err
+	}
+	if err := ensureGitignored(projectPath, privateFilesDir+"/"); err != nil {
+		printWarning(fmt.Sprintf("Could not add %s/ to .gitignore: %v", privateFilesDir, err))
+	}
+	return nil
+}
+
+func checkPrivateFiles(projectPath string) error {
+	output, err := ddevOutput(projectPath, "drush", "php:eval", `echo \Drupal\Core\Site\Settings::get('file_private_path', '');`)
+	if err != nil {
+		return fmt.Errorf("could not read file_private_path: %v", err)
+	}
+	if strings.TrimSpace(string(output)) == "" {
+		return fmt.Errorf("$settings['file_private_path'] is not set in settings.php")
+	}
+
+	output, err = ddevOutput(projectPath, "drush", "core:requirements", "--format=json")
+	if err != nil {
+		return fmt.Errorf("drush core:requirements failed: %v", err)
+	}
+	var requirements map[string]struct {
+		Value       string `json:"value"`
+		Description string `json:"description"`
+		Severity    string `json:"severity"`
+	}
+	if err := json.Unmarshal(output, &requirements); err != nil {
+		return fmt.Errorf("could not parse the status report: %v", err)
+	}
+	fileSystem, ok := requirements["file system"]
+	if !ok {
+		return fmt.Errorf("the status report has no file system entry")
+	}
+	if severity := strings.ToLower(fileSystem.Severity); severity == "error" || severity == "warning" {
+		detail := strings.TrimSpace(fileSystem.Description)
+		if detail == "" {
+			detail = fileSystem.Value
+		}
+		return fmt.Errorf("the status report flags the file system: %s", detail)
+	}
+	return nil
+}
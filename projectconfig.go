@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+type projectConfig struct {
+	Install      installSettings           `yaml:"install"`
+	Composer     composerPatch             `yaml:"composer"`
+	DDEV         ddevSettings              `yaml:"ddev"`
+	PHP          phpSettings               `yaml:"php"`
+	Webserver    webserverSettings         `yaml:"webserver"`
+	BasicAuth    basicAuthSettings         `yaml:"basic_auth"`
+	Licenses     licenseSettings           `yaml:"licenses"`
+	Locale       localeSettings            `yaml:"locale"`
+	Environments map[string]environmentDef `yaml:"environments"`
+	Release      releaseSettings           `yaml:"release"`
+	Scaffold     scaffoldSettings          `yaml:"scaffold"`
+}
+
+var projectConfigFiles = []string{"drupal-scripts.yml", ".drupal-scripts.yml"}
+
+var project projectConfig
+
+var projectConfigDir = "."
+
+func loadProjectConfig(path string) error {
+	if path == "" {
+		for _, candidate := range projectConfigFiles {
+			if _, err := os.Stat(candidate); err == nil {
+				path = candidate
+				break
+			}
+		}
+		if path == "" {
+			return nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to read %s", path))
+		return err
+	}
+	if err := yamlUnmarshal(data, &project); err != nil {
+		printError(fmt.Sprintf("Failed to parse %s: %v", path, err))
+		return err
+	}
+	projectConfigDir = filepath.Dir(path)
+	printStatus(fmt.Sprintf("Using project configuration from %s", path))
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	ifExistsAbort = "abort"
+	ifExistsAdopt = "adopt"
+)
+
+func normalizeProjectName(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "-")
+}
+
+func directoryInUse(path string) (bool, error) {
+	entries, err := os.ReadDir(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return len(entries) > 0, nil
+}
+
+func isDrupalComposerProject(path string) bool {
+	composer, err := readJSONFile(filepath.Join(path, "composer.json"))
+	if err != nil {
+		return false
+	}
+	for _, pkg := range []string{"drupal/core-recommended", "drupal/core"} {
+		if require, ok := composer.values["require"].(*jsonObject); ok {
+			if _, ok := require.values[pkg]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func resolveProjectDir(parent, name string) (string, bool, error) {
+	for {
+		projectPath := filepath.Join(parent, name)
+		inUse, err := directoryInUse(projectPath)
+		if err != nil {
+			return "", false, err
+		}
+		if !inUse {
+			opts.projectName = name
+			return projectPath, false, nil
+		}
+
+		adoptable := isDrupalComposerProject(projectPath)
+		printWarning(fmt.Sprintf("%s already exists and is not empty", projectPath))
+		choice := opts.ifExists
+		if choice == "" && opts.interactive {
+			choice = promptExistingDirChoice(adoptable)
+		}
+		switch choice {
+		case ifExistsAdopt:
+			if !adoptable {
+				return "", false, fmt.Errorf("%s has no composer.json requiring drupal/core, so it cannot be adopted", projectPath)
+			}
+			printStatus(fmt.Sprintf("Adopting the existing Drupal project in %s", projectPath))
+			opts.projectName = name
+			return projectPath, true, nil
+		case "rename":
+			name = normalizeProjectName(promptLine("New project name: "))
+			if name == "" {
+				return "", false, fmt.Errorf("empty project name")
+			}
+		default:
+			hint := "choose another --project-name"
+			if adoptable {
+				hint += " or pass --if-exists adopt to continue with the Drupal project there"
+			}
+			return "", false, fmt.Errorf("%s is not empty; %s", projectPath, hint)
+		}
+	}
+}
+
+func promptExistingDirChoice(adoptable bool) string {
+	notifyWaitingForInput("Choose what to do with the existing project directory")
+	fmt.Println("  [a] Abort")
+	fmt.Println("  [r] Use a different name")
+	if adoptable {
+		fmt.Println("  [d] Adopt the Drupal project already there")
+	}
+	switch strings.ToLower(promptLine("Choice [a]: ")) {
+	case "r", "rename":
+		return "rename"
+	case "d", "adopt":
+		if adoptable {
+			return ifExistsAdopt
+		}
+	}
+	return ifExistsAbort
+}
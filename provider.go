@@ -0,0 +1,308 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const gib = 1 << 30
+
+type hostInfo struct {
+	appleSilicon bool
+	rosetta      bool
+	memoryBytes  uint64
+	cpus         int
+}
+
+type providerRecommendation struct {
+	provider   string
+	reason     string
+	colimaArgs []string
+}
+
+func sysctlValue(name string) string {
+	output, err := exec.Command("sysctl", "-n", name).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func detectHost() hostInfo {
+	info := hostInfo{cpus: runtime.NumCPU()}
+	switch runtime.GOOS {
+	case "darwin":
+		info.appleSilicon = runtime.GOARCH == "arm64" || sysctlValue("hw.optional.arm64") == "1"
+		info.rosetta = sysctlValue("sysctl.proc_translated") == "1"
+		info.memoryBytes, _ = strconv.ParseUint(sysctlValue("hw.memsize"), 10, 64)
+	case "linux":
+		if f, err := os.Open("/proc/meminfo"); err == nil {
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				fields := strings.Fields(scanner.Text())
+				if len(fields) >= 2 && fields[0] == "MemTotal:" {
+					kb, _ := strconv.ParseUint(fields[1], 10, 64)
+					info.memoryBytes = kb * 1024
+					break
+				}
+			}
+			f.Close()
+		}
+	}
+	return info
+}
+
+func dockerDesktopInstalled() bool {
+	if _, err := os.Stat("/Applications/Docker.app"); err == nil {
+		return true
+	}
+	return brewPackageInstalled("docker-desktop")
+}
+
+func colimaConfigured() bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(filepath.Join(home, ".colima", "default", "colima.yaml"))
+	return err == nil
+}
+
+func colimaStartArgs(host hostInfo) []string {
+	cpus := host.cpus / 2
+	if cpus < 2 {
+		cpus = 2
+	}
+	memory := int(host.memoryBytes / gib / 4)
+	if memory < 4 {
+		memory = 4
+	}
+	if memory > 8 {
+		memory = 8
+	}
+
+	args := []string{"--cpu", strconv.Itoa(cpus), "--memory", strconv.Itoa(memory)}
+	if host.appleSilicon {
+		args = append(args, "--vm-type", "vz", "--vz-rosetta", "--mount-type", "virtiofs")
+	}
+	return args
+}
+
+func recommendProvider(host hostInfo) providerRecommendation {
+	rec := providerRecommendation{colimaArgs: colimaStartArgs(host)}
+	memGiB := host.memoryBytes / gib
+	desktop := dockerDesktopInstalled()
+	colima := commandExists("colima")
+
+	switch {
+	case colima && !desktop:
+		rec.provider, rec.reason = "colima", "Colima is already installed"
+	case desktop && !colima:
+		rec.provider, rec.reason = "docker", "Docker Desktop is already installed"
+	case memGiB > 0 && memGiB < 16:
+		rec.provider, rec.reason = "colima", fmt.Sprintf("%d GB RAM; Colima has a smaller footprint than Docker Desktop", memGiB)
+	case desktop:
+		rec.provider, rec.reason = "docker", "Docker Desktop is installed and there is enough RAM for it"
+	default:
+		rec.provider, rec.reason = "colima", "free, lightweight and scriptable"
+	}
+
+	if rec.provider == "colima" && host.appleSilicon {
+		rec.reason += "; Apple Silicon will use the vz VM with Rosetta for amd64 images"
+	}
+	return rec
+}
+
+func providerLabel(provider string) string {
+	if provider == "colima" {
+		return "Colima"
+	}
+	return "Docker Desktop"
+}
+
+type colimaInstance struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	CPUs   int    `json:"cpus"`
+	Memory uint64 `json:"memory"`
+	Disk   uint64 `json:"disk"`
+}
+
+func currentColimaInstance() (*colimaInstance, error) {
+	output, err := exec.Command("colima", "list", "--json").Output()
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		var inst colimaInstance
+		if err := json.Unmarshal([]byte(line), &inst); err != nil {
+			continue
+		}
+		if inst.Name == "default" {
+			return &inst, nil
+		}
+	}
+	return nil, fmt.Errorf("no default colima instance")
+}
+
+func runningDDEVProjects() []string {
+	output, err := exec.Command("ddev", "list", "--json-output").Output()
+	if err != nil {
+		return nil
+	}
+	var result struct {
+		Raw []struct {
+			Name   string `json:"name"`
+			Status string `json:"status"`
+		} `json:"raw"`
+	}
+	if err := json.Unmarshal(output, &result); err != nil {
+		return nil
+	}
+	var running []string
+	for _, p := range result.Raw {
+		if p.Status == "running" {
+			running = append(running, p.Name)
+		}
+	}
+	return running
+}
+
+func confirm(question string, assumeYes bool) bool {
+	if assumeYes {
+		return true
+	}
+	if !opts.interactive {
+		printError("Confirmation required; re-run with --yes to proceed non-interactively")
+		return false
+	}
+	answer := strings.ToLower(promptLine(question + " (y/N): "))
+	return answer == "y" || answer == "yes"
+}
+
+func tuneColima(cpus, memory, disk int, assumeYes bool) int {
+	current, err := currentColimaInstance()
+	if err != nil {
+		printError("No Colima VM found; run the installer or 'colima start' first")
+		return 1
+	}
+
+	currentDisk := int(current.Disk / gib)
+	if disk > 0 && disk < currentDisk {
+		printError(fmt.Sprintf("Colima cannot shrink a disk (current %d GB, requested %d GB)", currentDisk, disk))
+		return 2
+	}
+
+	printStatus("Colima VM resources:")
+	fmt.Printf("  CPUs:   %d -> %d\n", current.CPUs, cpus)
+	fmt.Printf("  Memory: %d GB -> %d GB\n", current.Memory/gib, memory)
+	if disk > 0 {
+		fmt.Printf("  Disk:   %d GB -> %d GB\n", currentDisk, disk)
+	}
+
+	running := runningDDEVProjects()
+	if len(running) > 0 {
+		printWarning(fmt.Sprintf("These DDEV projects will be stopped while Colima restarts: %s", strings.Join(running, ", ")))
+	} else {
+		printWarning("Colima will be stopped and restarted; containers will be unavailable for a minute or two.")
+	}
+	if !confirm("Apply the new Colima settings now?", assumeYes) {
+		printStatus("No changes made")
+		return 1
+	}
+
+	if len(running) > 0 {
+		if err := runCommand("ddev", "poweroff"); err != nil {
+			printError("Failed to stop DDEV projects")
+			return 1
+		}
+	}
+	if err := runCommand("colima", "stop"); err != nil {
+		printError("Failed to stop Colima")
+		return 1
+	}
+
+	args := []string{"start", "--cpu", strconv.Itoa(cpus), "--memory", strconv.Itoa(memory)}
+	if disk > 0 {
+		args = append(args, "--disk", strconv.Itoa(disk))
+	}
+	if err := runCommand("colima", args...); err != nil {
+		printError("Failed to restart Colima with the new settings")
+		return 1
+	}
+	printSuccess("✓ Colima restarted with new resources")
+
+	if len(running) > 0 {
+		printStatus("Restarting previously running DDEV projects...")
+		if err := runCommand("ddev", append([]string{"start"}, running...)...); err != nil {
+			printWarning("Some projects failed to restart; run 'ddev start' in each project")
+		}
+	}
+	return 0
+}
+
+func tuneDockerDesktop(cpus, memory int) int {
+	output, err := exec.Command("docker", "info", "--format", "{{.NCPU}} {{.MemTotal}}").Output()
+	if err != nil {
+		printError("Docker Desktop is not running")
+		return 1
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 2 {
+		memTotal, _ := strconv.ParseUint(fields[1], 10, 64)
+		printStatus("Docker Desktop resources:")
+		fmt.Printf("  CPUs:   %s (recommended %d)\n", fields[0], cpus)
+		fmt.Printf("  Memory: %d GB (recommended %d GB)\n", memTotal/gib, memory)
+	}
+	printWarning("Docker Desktop resources cannot be changed from the command line.")
+	fmt.Println("Open Docker Desktop > Settings > Resources, apply the recommended values, then click 'Apply & restart'.")
+	fmt.Println("Running DDEV projects will be stopped while Docker Desktop restarts; run 'ddev start' in each afterwards.")
+	return 0
+}
+
+func runProvider(args []string) int {
+	if len(args) == 0 || args[0] != "tune" {
+		printError("Usage: install-drupal provider tune [--cpu N] [--memory GB] [--disk GB] [--yes]")
+		return 2
+	}
+
+	host := detectHost()
+	recommended := colimaStartArgs(host)
+	defaultCPU, _ := strconv.Atoi(recommended[1])
+	defaultMemory, _ := strconv.Atoi(recommended[3])
+
+	fs := flag.NewFlagSet("provider tune", flag.ContinueOnError)
+	provider := fs.String("provider", "", "Provider to tune: colima or docker (default: the active one)")
+	cpus := fs.Int("cpu", defaultCPU, "Number of CPUs")
+	memory := fs.Int("memory", defaultMemory, "Memory in GB")
+	disk := fs.Int("disk", 0, "Disk size in GB (Colima only; can only grow)")
+	assumeYes := fs.Bool("yes", false, "Do not ask for confirmation")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	opts.interactive = stdinIsTerminal()
+
+	if *provider == "" {
+		*provider = "docker"
+		if strings.HasPrefix(currentDockerContext(), "colima") {
+			*provider = "colima"
+		}
+	}
+
+	switch *provider {
+	case "colima":
+		return tuneColima(*cpus, *memory, *disk, *assumeYes)
+	case "docker":
+		return tuneDockerDesktop(*cpus, *memory)
+	}
+	printError(fmt.Sprintf("Unknown provider %q (expected colima or docker)", *provider))
+	return 2
+}
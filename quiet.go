@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+)
+
+const spinnerWidth = 60
+
+var (
+	spinnerFrames      = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+	asciiSpinnerFrames = []string{"|", "/", "-", `\`}
+)
+
+func quietCommand(cmd *exec.Cmd) bool {
+	if !opts.quiet || opts.verbose >= 2 || cmd.Stdin == os.Stdin {
+		return false
+	}
+	return cmd.Stdout == os.Stdout || cmd.Stdout == os.Stderr || cmd.Stderr == os.Stdout || cmd.Stderr == os.Stderr
+}
+
+type spinner struct {
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+func startSpinner(label string) *spinner {
+	if !isTerminal(os.Stderr) || jsonOutput() {
+		return nil
+	}
+	if runes := []rune(label); len(runes) > spinnerWidth {
+		label = string(runes[:spinnerWidth-1]) + "…"
+	}
+	frames := spinnerFrames
+	if asciiOnly {
+		frames = asciiSpinnerFrames
+		label = asciiReplacer.Replace(label)
+	}
+	s := &spinner{done: make(chan struct{}), stopped: make(chan struct{})}
+	go func() {
+		defer close(s.stopped)
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for i := 0; ; i++ {
+			fmt.Fprintf(os.Stderr, "\r%s %s", frames[i%len(frames)], label)
+			select {
+			case <-s.done:
+				fmt.Fprint(os.Stderr, "\r\033[K")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+func (s *spinner) stop() {
+	if s == nil {
+		return
+	}
+	close(s.done)
+	<-s.stopped
+}
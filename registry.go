@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+type managedProject struct {
+	Name      string    `json:"name"`
+	Path      string    `json:"path"`
+	URL       string    `json:"url"`
+	Modules   []string  `json:"modules,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func registryPath() (string, error) {
+	dir, err := userConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "projects.json"), nil
+}
+
+func loadRegistry() ([]managedProject, error) {
+	path, err := registryPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var projects []managedProject
+	if err := json.Unmarshal(data, &projects); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return projects, nil
+}
+
+func saveRegistry(projects []managedProject) error {
+	path, err := registryPath()
+	if err != nil {
+		return err
+	}
+	sort.Slice(projects, func(i, j int) bool { return projects[i].Name < projects[j].Name })
+	data, err := json.MarshalIndent(projects, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(path, append(data, '\n'))
+}
+
+func registerProject(projectPath, siteURL string, modules []string) {
+	projects, err := loadRegistry()
+	if err != nil {
+		printWarning(fmt.Sprintf("Could not read project registry: %v", err))
+		return
+	}
+
+	entry := managedProject{Name: filepath.Base(projectPath), Path: projectPath, URL: siteURL, Modules: modules, CreatedAt: time.Now()}
+	replaced := false
+	for i, p := range projects {
+		if p.Path == projectPath {
+			entry.CreatedAt = p.CreatedAt
+			if entry.Modules == nil {
+				entry.Modules = p.Modules
+			}
+			projects[i] = entry
+			replaced = true
+		}
+	}
+	if !replaced {
+		projects = append(projects, entry)
+	}
+
+	if err := saveRegistry(projects); err != nil {
+		printWarning(fmt.Sprintf("Could not update project registry: %v", err))
+	}
+}
+
+func findRegisteredProject(projectPath string) (managedProject, bool) {
+	projects, err := loadRegistry()
+	if err != nil {
+		return managedProject{}, false
+	}
+	for _, p := range projects {
+		if p.Path == projectPath {
+			return p, true
+		}
+	}
+	return managedProject{}, false
+}
+
+func unregisterProject(projectPath string) error {
+	projects, err := loadRegistry()
+	if err != nil {
+		return err
+	}
+	var kept []managedProject
+	for _, p := range projects {
+		if p.Path != projectPath {
+			kept = append(kept, p)
+		}
+	}
+	return saveRegistry(kept)
+}
+
+func findRegisteredProjectByName(name string) (managedProject, bool) {
+	projects, err := loadRegistry()
+	if err != nil {
+		return managedProject{}, false
+	}
+	for _, p := range projects {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return managedProject{}, false
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type releaseSettings struct {
+	Image      string `yaml:"image"`
+	Dockerfile string `yaml:"dockerfile"`
+	Changelog  string `yaml:"changelog"`
+}
+
+var (
+	conventionalCommitPattern = regexp.MustCompile(`^(\w+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+	semverPattern             = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)$`)
+)
+
+type releaseCommit struct {
+	hash     string
+	kind     string
+	scope    string
+	summary  string
+	breaking bool
+}
+
+var changelogSections = []struct{ kind, title string }{
+	{"feat", "Features"},
+	{"fix", "Bug fixes"},
+	{"perf", "Performance"},
+}
+
+type semver [3]int
+
+func (v semver) String() string {
+	return fmt.Sprintf("v%d.%d.%d", v[0], v[1], v[2])
+}
+
+func parseSemver(s string) (semver, bool) {
+	m := semverPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return semver{}, false
+	}
+	var v semver
+	for i := range v {
+		v[i], _ = strconv.Atoi(m[i+1])
+	}
+	return v, true
+}
+
+func (v semver) bump(part string) semver {
+	switch part {
+	case "major":
+		return semver{v[0] + 1, 0, 0}
+	case "minor":
+		return semver{v[0], v[1] + 1, 0}
+	}
+	return semver{v[0], v[1], v[2] + 1}
+}
+
+func gitIn(projectPath string, args ...string) (string, error) {
+	out, err := runCommandOutput("git", append([]string{"-C", projectPath}, args...)...)
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(out))
+	}
+	return strings.TrimSpace(out), nil
+}
+
+func releaseCommits(projectPath, tag string) ([]releaseCommit, error) {
+	args := []string{"log", "--format=%h%x1f%s%x1f%b%x1e"}
+	if tag != "" {
+		args = append(args, tag+"..HEAD")
+	}
+	out, err := gitIn(projectPath, args...)
+	if err != nil {
+		return nil, err
+	}
+	var commits []releaseCommit
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(strings.TrimSpace(record), "\x1f", 3)
+		if len(fields) < 2 {
+			continue
+		}
+		c := releaseCommit{hash: fields[0], summary: fields[1]}
+		if m := conventionalCommitPattern.FindStringSubmatch(fields[1]); m != nil {
+			c.kind, c.scope, c.summary, c.breaking = strings.ToLower(m[1]), m[2], m[4], m[3] == "!"
+		}
+		if len(fields) == 3 && strings.Contains(fields[2], "BREAKING CHANGE") {
+			c.breaking = true
+		}
+		if c.kind == "chore" && c.scope == "release" {
+			continue
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+func releaseBump(commits []releaseCommit) string {
+	bump := "patch"
+	for _, c := range commits {
+		if c.breaking {
+			return "major"
+		}
+		if c.kind == "feat" {
+			bump = "minor"
+		}
+	}
+	return bump
+}
+
+func changelogEntry(c releaseCommit) string {
+	if c.scope != "" {
+		return fmt.Sprintf("- **%s:** %s (%s)\n", c.scope, c.summary, c.hash)
+	}
+	return fmt.Sprintf("- %s (%s)\n", c.summary, c.hash)
+}
+
+func renderChangelog(version semver, date time.Time, commits []releaseCommit) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s - %s\n", version, date.Format("2006-01-02"))
+	section := func(title string, match func(releaseCommit) bool) {
+		var entries []string
+		for _, c := range commits {
+			if match(c) {
+				entries = append(entries, changelogEntry(c))
+			}
+		}
+		if len(entries) > 0 {
+			fmt.Fprintf(&b, "\n### %s\n\n%s", title, strings.Join(entries, ""))
+		}
+	}
+	section("Breaking changes", func(c releaseCommit) bool { return c.breaking })
+	known := map[string]bool{}
+	for _, s := range changelogSections {
+		kind := s.kind
+		known[kind] = true
+		section(s.title, func(c releaseCommit) bool { return !c.breaking && c.kind == kind })
+	}
+	section("Other changes", func(c releaseCommit) bool { return !c.breaking && !known[c.kind] })
+	return b.String()
+}
+
+func prependChangelog(path, release string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	existing := string(data)
+	header := "# Changelog\n\n"
+	if strings.HasPrefix(existing, "# ") {
+		if i := strings.Index(existing, "\n"); i >= 0 {
+			header = existing[:i+1] + "\n"
+			existing = strings.TrimLeft(existing[i+1:], "\n")
+		}
+	}
+	content := header + release
+	if existing != "" {
+		content += "\n" + existing
+	}
+	return writeFile(path, []byte(content))
+}
+
+func buildReleaseImage(projectPath, image, dockerfile string, version semver, push bool) error {
+	tags := []string{image + ":" + version.String(), image + ":latest"}
+	args := []string{"build", "-f", dockerfile}
+	for _, tag := range tags {
+		args = append(args, "-t", tag)
+	}
+	args = append(args, projectPath)
+	printStatus(fmt.Sprintf("Building %s", tags[0]))
+	if err := runCommand("docker", args...); err != nil {
+		return fmt.Errorf("docker build failed: %v", err)
+	}
+	if !push {
+		return nil
+	}
+	for _, tag := range tags {
+		if err := runCommand("docker", "push", tag); err != nil {
+			return fmt.Errorf("docker push %s failed: %v", tag, err)
+		}
+	}
+	return nil
+}
+
+func runRelease(args []string) int {
+	fs := flag.NewFlagSet("release", flag.ContinueOnError)
+	path := fs.String("path", ".", "Path to the project repository")
+	bump := fs.String("bump", "auto", "Version part to bump: auto (from the commits), major, minor or patch")
+	version := fs.String("version", "", "Release this exact version instead of bumping (e.g. 2.0.0)")
+	dryRun := fs.Bool("dry-run", false, "Print the version and changelog without changing anything")
+	push := fs.Bool("push", false, "Push the release commit and tag to origin")
+	image := fs.String("image", "", "Build the production image with this name (default: release.image)")
+	pushImage := fs.Bool("push-image", false, "Push the built image")
+	yes := fs.Bool("yes", false, "Release without asking for confirmation")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	opts.interactive = stdinIsTerminal()
+	if err := loadProjectConfigFrom(projectPath); err != nil {
+		return 1
+	}
+	settings := project.Release
+	if *bump != "auto" && *bump != "major" && *bump != "minor" && *bump != "patch" {
+		printError(fmt.Sprintf("Invalid --bump %q (expected auto, major, minor or patch)", *bump))
+		return 2
+	}
+
+	if status, err := gitIn(projectPath, "status", "--porcelain"); err != nil {
+		printError(err.Error())
+		return 1
+	} else if status != "" && !*dryRun {
+		printError("The working tree has uncommitted changes; commit or stash them before releasing")
+		return 1
+	}
+
+	previous, _ := gitIn(projectPath, "describe", "--tags", "--abbrev=0", "--match", "v[0-9]*")
+	current, ok := parseSemver(previous)
+	if previous != "" && !ok && *version == "" {
+		printError(fmt.Sprintf("The latest tag %s is not vMAJOR.MINOR.PATCH, so the next version cannot be worked out; pass --version", previous))
+		return 1
+	}
+	commits, err := releaseCommits(projectPath, previous)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if len(commits) == 0 {
+		printWarning(fmt.Sprintf("No commits since %s; nothing to release", firstNonEmpty(previous, "the start of the history")))
+		return 0
+	}
+
+	next := current.bump(*bump)
+	if *bump == "auto" {
+		next = current.bump(releaseBump(commits))
+	}
+	if *version != "" {
+		v, ok := parseSemver(*version)
+		if !ok {
+			printError(fmt.Sprintf("Invalid --version %q (expected MAJOR.MINOR.PATCH)", *version))
+			return 2
+		}
+		next = v
+	}
+	if _, err := gitIn(projectPath, "rev-parse", "--verify", "--quiet", "refs/tags/"+next.String()); err == nil {
+		printError(fmt.Sprintf("Tag %s already exists", next))
+		return 1
+	}
+
+	changelog := renderChangelog(next, time.Now(), commits)
+	printStatus(fmt.Sprintf("Releasing %s (previous: %s, %d commits)", next, firstNonEmpty(previous, "none"), len(commits)))
+	fmt.Println()
+	fmt.Print(changelog)
+	fmt.Println()
+	if *dryRun {
+		return 0
+	}
+	if !confirm(fmt.Sprintf("Commit the changelog and tag %s?", next), *yes) {
+		return 1
+	}
+
+	changelogPath := filepath.Join(projectPath, firstNonEmpty(settings.Changelog, "CHANGELOG.md"))
+	if err := prependChangelog(changelogPath, changelog); err != nil {
+		printError(fmt.Sprintf("Failed to update the changelog: %v", err))
+		return 1
+	}
+	steps := [][]string{
+		{"add", changelogPath},
+		{"commit", "--quiet", "-m", "chore(release): " + next.String()},
+		{"tag", "-a", next.String(), "-m", "Release " + next.String() + "\n\n" + changelog},
+	}
+	if *push {
+		steps = append(steps, []string{"push", "--follow-tags", "origin", "HEAD"})
+	}
+	for _, step := range steps {
+		if _, err := gitIn(projectPath, step...); err != nil {
+			printError(err.Error())
+			return 1
+		}
+	}
+	printSuccess(fmt.Sprintf("✓ Tagged %s", next))
+
+	if imageName := firstNonEmpty(*image, settings.Image); imageName != "" {
+		dockerfile := filepath.Join(projectPath, firstNonEmpty(settings.Dockerfile, "Dockerfile"))
+		if err := buildReleaseImage(projectPath, imageName, dockerfile, next, *pushImage); err != nil {
+			printError(err.Error())
+			return 1
+		}
+		printSuccess(fmt.Sprintf("✓ Built %s:%s", imageName, next))
+	}
+	sendWebhooks("release", "Released "+next.String(), fmt.Sprintf("%s: %d commits since %s", filepath.Base(projectPath), len(commits), firstNonEmpty(previous, "the first commit")))
+	return 0
+}
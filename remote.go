@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+var readOnlyDrushCommands = []string{
+	"status", "st", "core:status", "core-status",
+	"version", "list", "help",
+	"cache:rebuild", "cache-rebuild", "cr", "rebuild",
+	"cache:get", "cache-get", "cg",
+	"config:get", "config-get", "cget",
+	"config:status", "config-status", "cst",
+	"core:requirements", "core-requirements", "rq",
+	"core:route", "route",
+	"updatedb:status", "updatedb-status", "updbst",
+	"state:get", "state-get", "sget",
+	"pm:list", "pm-list", "pml",
+	"pm:security", "pm-security", "sec",
+	"watchdog:show", "watchdog-show", "wd-show", "ws",
+	"watchdog:list", "watchdog-list", "wd-list",
+	"user:information", "user-information", "uinf",
+	"role:list", "rls",
+	"site:alias", "site-alias", "sa",
+	"queue:list",
+	"migrate:status", "ms",
+	"search-api:status", "sapi-s",
+	"views:list", "vl",
+	"field:info", "fi",
+}
+
+var (
+	drushValueOptions = []string{
+		"--uri", "-l", "--root", "-r", "--config", "--alias-path", "--include",
+		"--define", "-D", "--ssh-options",
+	}
+	drushFlagOptions = []string{
+		"--yes", "-y", "--no", "--no-interaction", "-n", "--verbose", "-v", "-vv", "-vvv",
+		"--debug", "-d", "--quiet", "-q", "--simulate", "-s", "--ansi", "--no-ansi",
+	}
+)
+
+func drushCommandName(args []string) string {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case !strings.HasPrefix(arg, "-"):
+			return arg
+		case strings.Contains(arg, "="), containsString(drushFlagOptions, arg):
+		case containsString(drushValueOptions, arg):
+			i++
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+func runRemote(args []string) int {
+	if len(args) == 0 || args[0] != "drush" {
+		printError("Usage: install-drupal remote drush [--yes] <environment|@alias> <drush command> [args...]")
+		return 2
+	}
+	fs := flag.NewFlagSet("remote drush", flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the local project")
+	yes := fs.Bool("yes", false, "Run commands that may change data without asking")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() < 2 {
+		printError("Usage: install-drupal remote drush [--yes] <environment|@alias> <drush command> [args...]")
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	opts.interactive = stdinIsTerminal()
+
+	target := fs.Arg(0)
+	alias, err := resolveEnvironment(projectPath, target)
+	if err != nil {
+		printError(err.Error())
+		return 2
+	}
+	drushArgs := fs.Args()[1:]
+	command := drushCommandName(drushArgs)
+	if !containsString(readOnlyDrushCommands, command) {
+		label := alias
+		if envs, err := projectEnvironments(projectPath); err == nil && envs[target].URL != "" {
+			label = fmt.Sprintf("%s (%s)", alias, envs[target].URL)
+		}
+		if command == "" {
+			printWarning(fmt.Sprintf("Cannot tell which drush command runs on %s, so it may change data", label))
+		} else {
+			printWarning(fmt.Sprintf("drush %s is not a known read-only command and may change data on %s", command, label))
+		}
+		if !confirm(fmt.Sprintf("Run 'drush %s' on %s?", strings.Join(drushArgs, " "), target), *yes) {
+			return 1
+		}
+	}
+
+	cmd := ddevCommand(projectPath, drushTarget(alias, drushArgs...)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return exitErr.ExitCode()
+		}
+		printError(err.Error())
+		return 1
+	}
+	return 0
+}
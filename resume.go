@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const installStateFile = ".drupal-scripts-install.json"
+
+type installState struct {
+	Args      []string       `json:"args"`
+	Answers   map[string]any `json:"answers"`
+	Completed []string       `json:"completed"`
+	project   string
+}
+
+var repeatOnResume = []string{"prerequisites", "ddev-start"}
+
+var checkpoint *installState
+
+var resumeAnswers map[string]any
+
+func readInstallState(projectPath string) (*installState, error) {
+	path := filepath.Join(projectPath, installStateFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s has no unfinished install to resume (no %s)", projectPath, installStateFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := &installState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	state.project = projectPath
+	return state, nil
+}
+
+func withoutResumeFlag(args []string) []string {
+	var kept []string
+	for i := 0; i < len(args); i++ {
+		name := strings.TrimLeft(args[i], "-")
+		switch {
+		case name == "resume" && args[i] != name:
+			i++
+		case strings.HasPrefix(name, "resume=") && args[i] != name:
+		default:
+			kept = append(kept, args[i])
+		}
+	}
+	return kept
+}
+
+func prepareResume(args []string) error {
+	projectPath, err := filepath.Abs(opts.resume)
+	if err != nil {
+		return err
+	}
+	state, err := readInstallState(projectPath)
+	if err != nil {
+		printError(err.Error())
+		return err
+	}
+
+	resumeAnswers = state.Answers
+	for name, value := range state.Answers {
+		answers[name] = value
+	}
+	opts = options{}
+	if err := parseFlags(append(append([]string(nil), state.Args...), withoutResumeFlag(args)...)); err != nil {
+		return err
+	}
+	checkpoint = state
+	printStatus(fmt.Sprintf("Resuming the install in %s; skipping %d completed steps", projectPath, len(state.Completed)))
+	return nil
+}
+
+func startCheckpoint(args []string) {
+	if opts.dryRun {
+		return
+	}
+	checkpoint = &installState{Args: withoutResumeFlag(args)}
+}
+
+func (s *installState) done(step string) bool {
+	return s != nil && containsString(s.Completed, step)
+}
+
+func (s *installState) setProject(projectPath string) {
+	if s == nil || s.project != "" {
+		return
+	}
+	s.project = projectPath
+	if err := ensureGitignored(projectPath, installStateFile); err != nil {
+		printWarning(fmt.Sprintf("Could not add %s to .gitignore: %v", installStateFile, err))
+	}
+	s.save()
+}
+
+func (s *installState) complete(step string) {
+	if s == nil || s.done(step) || containsString(repeatOnResume, step) {
+		return
+	}
+	s.Completed = append(s.Completed, step)
+	s.save()
+}
+
+func (s *installState) save() {
+	if s.project == "" {
+		return
+	}
+	s.Answers = answers
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := writeSecretFile(filepath.Join(s.project, installStateFile), append(data, '\n')); err != nil {
+		printWarning(fmt.Sprintf("Could not save the install state: %v", err))
+	}
+}
+
+func (s *installState) failed(step string) {
+	if s == nil || s.project == "" {
+		return
+	}
+	printStatus(fmt.Sprintf("Fix the problem, then continue from %s with: install-drupal --resume %s", step, s.project))
+}
+
+func (s *installState) finish() {
+	if s == nil || s.project == "" {
+		return
+	}
+	if err := removeFile(filepath.Join(s.project, installStateFile)); err != nil {
+		printWarning(fmt.Sprintf("Could not remove %s: %v", installStateFile, err))
+	}
+}
+
+func restoreSkippedState(projectPath string) error {
+	if checkpoint.done("settings") {
+		existingConfig = hasExistingConfig(projectPath)
+	}
+	if checkpoint.done("ddev-config") && (opts.basicAuth || project.BasicAuth.Enabled) {
+		settings := project.BasicAuth
+		if opts.basicAuthUser != "" {
+			settings.Username = opts.basicAuthUser
+		}
+		creds, err := loadOrCreateBasicAuth(filepath.Base(projectPath), settings)
+		if err != nil {
+			return err
+		}
+		siteBasicAuth = creds
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+func (d dependency) purl() string {
+	name := d.name
+	if d.ecosystem == "npm" && strings.HasPrefix(name, "@") {
+		name = "%40" + strings.TrimPrefix(name, "@")
+	}
+	return "pkg:" + d.ecosystem + "/" + name + "@" + url.PathEscape(strings.TrimPrefix(d.version, "v"))
+}
+
+func sbomUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+func sbomLicenseExpression(licenses []string) string {
+	if len(licenses) == 0 {
+		return "NOASSERTION"
+	}
+	if len(licenses) == 1 {
+		return licenses[0]
+	}
+	parts := make([]string, len(licenses))
+	for i, l := range licenses {
+		if strings.Contains(l, " ") {
+			l = "(" + l + ")"
+		}
+		parts[i] = l
+	}
+	return "(" + strings.Join(parts, " OR ") + ")"
+}
+
+func cycloneDXDocument(projectName string, deps []dependency) *jsonObject {
+	doc := newJSONObject()
+	doc.set("bomFormat", "CycloneDX")
+	doc.set("specVersion", "1.5")
+	doc.set("serialNumber", "urn:uuid:"+sbomUUID())
+	doc.set("version", 1)
+
+	metadata := doc.object("metadata")
+	metadata.set("timestamp", time.Now().UTC().Format(time.RFC3339))
+	tool := newJSONObject()
+	tool.set("name", "drupal-scripts")
+	metadata.object("tools").set("components", []any{tool})
+	root := metadata.object("component")
+	root.set("type", "application")
+	root.set("name", projectName)
+
+	var components []any
+	for _, dep := range deps {
+		c := newJSONObject()
+		c.set("type", "library")
+		c.set("bom-ref", dep.purl())
+		c.set("name", dep.name)
+		c.set("version", dep.version)
+		c.set("purl", dep.purl())
+		if len(dep.licenses) > 0 {
+			license := newJSONObject()
+			license.set("expression", sbomLicenseExpression(dep.licenses))
+			c.set("licenses", []any{license})
+		}
+		if dep.dev {
+			c.set("scope", "optional")
+		}
+		property := newJSONObject()
+		property.set("name", "drupal-scripts:source")
+		property.set("value", dep.source)
+		c.set("properties", []any{property})
+		components = append(components, c)
+	}
+	doc.set("components", components)
+	return doc
+}
+
+func spdxID(dep dependency) string {
+	id := dep.ecosystem + "-" + dep.name + "-" + dep.version
+	return "SPDXRef-" + strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '-'
+	}, id)
+}
+
+func spdxDocument(projectName string, deps []dependency) *jsonObject {
+	doc := newJSONObject()
+	doc.set("spdxVersion", "SPDX-2.3")
+	doc.set("dataLicense", "CC0-1.0")
+	doc.set("SPDXID", "SPDXRef-DOCUMENT")
+	doc.set("name", projectName)
+	doc.set("documentNamespace", "https://spdx.org/spdxdocs/"+url.PathEscape(projectName)+"-"+sbomUUID())
+	creation := doc.object("creationInfo")
+	creation.set("created", time.Now().UTC().Format(time.RFC3339))
+	creation.set("creators", []any{"Tool: drupal-scripts"})
+
+	var packages, relationships []any
+	for _, dep := range deps {
+		p := newJSONObject()
+		p.set("SPDXID", spdxID(dep))
+		p.set("name", dep.name)
+		p.set("versionInfo", dep.version)
+		p.set("downloadLocation", "NOASSERTION")
+		p.set("filesAnalyzed", false)
+		p.set("licenseConcluded", "NOASSERTION")
+		p.set("licenseDeclared", sbomLicenseExpression(dep.licenses))
+		ref := newJSONObject()
+		ref.set("referenceCategory", "PACKAGE-MANAGER")
+		ref.set("referenceType", "purl")
+		ref.set("referenceLocator", dep.purl())
+		p.set("externalRefs", []any{ref})
+		packages = append(packages, p)
+
+		rel := newJSONObject()
+		rel.set("spdxElementId", "SPDXRef-DOCUMENT")
+		rel.set("relationshipType", "DESCRIBES")
+		rel.set("relatedSpdxElement", spdxID(dep))
+		relationships = append(relationships, rel)
+	}
+	doc.set("packages", packages)
+	doc.set("relationships", relationships)
+	return doc
+}
+
+func runSBOM(args []string) int {
+	fs := flag.NewFlagSet("sbom", flag.ContinueOnError)
+	path := fs.String("path", ".", "Path to the Drupal project")
+	format := fs.String("format", "cyclonedx", "SBOM format: cyclonedx or spdx")
+	output := fs.String("output", "", "File to write (default: sbom.cdx.json or sbom.spdx.json in the project)")
+	noDev := fs.Bool("no-dev", false, "Leave out development dependencies")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	deps, err := collectDependencies(projectPath)
+	if err != nil {
+		printError(fmt.Sprintf("Cannot read dependencies: %v", err))
+		return 1
+	}
+	if *noDev {
+		var prod []dependency
+		for _, dep := range deps {
+			if !dep.dev {
+				prod = append(prod, dep)
+			}
+		}
+		deps = prod
+	}
+
+	projectName := filepath.Base(projectPath)
+	var doc *jsonObject
+	var defaultName string
+	switch *format {
+	case "cyclonedx":
+		doc, defaultName = cycloneDXDocument(projectName, deps), "sbom.cdx.json"
+	case "spdx":
+		doc, defaultName = spdxDocument(projectName, deps), "sbom.spdx.json"
+	default:
+		printError(fmt.Sprintf("Unknown --format %q (expected cyclonedx or spdx)", *format))
+		return 2
+	}
+
+	dest := *output
+	if dest == "" {
+		dest = filepath.Join(projectPath, defaultName)
+	}
+	if dest == "-" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "    ")
+		if err := enc.Encode(doc); err != nil {
+			printError(err.Error())
+			return 1
+		}
+		return 0
+	}
+	if err := writeJSONFile(dest, doc); err != nil {
+		printError(fmt.Sprintf("Failed to write %s: %v", dest, err))
+		return 1
+	}
+	printSuccess(fmt.Sprintf("✓ Wrote %s SBOM with %d components to %s", *format, len(deps), dest))
+	return 0
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/template"
+)
+
+//go:embed scaffold/*.tmpl
+var embeddedScaffoldTemplates embed.FS
+
+var scaffoldFuncs = template.FuncMap{
+	"json": func(v any) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+func renderScaffold(name string, data any) ([]byte, error) {
+	body, err := embeddedScaffoldTemplates.ReadFile("scaffold/" + name + ".tmpl")
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template.New(name).Funcs(scaffoldFuncs).Option("missingkey=error").Parse(string(body))
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func writeScaffoldFile(path string, data []byte, force bool) error {
+	return writeScaffold(path, data, force, writeFile)
+}
+
+func writeScaffoldExecutable(path string, data []byte, force bool) error {
+	return writeScaffold(path, data, force, writeExecutable)
+}
+
+func writeScaffold(path string, data []byte, force bool, write func(string, []byte) error) error {
+	if _, err := os.Stat(path); err == nil && !force {
+		printWarning(fmt.Sprintf("%s already exists; use --force to overwrite", path))
+		return nil
+	}
+	if err := write(path, data); err != nil {
+		printError(fmt.Sprintf("Failed to write %s", path))
+		return err
+	}
+	printSuccess(fmt.Sprintf("✓ Wrote %s", path))
+	return nil
+}
+
+func objectKeys(obj *jsonObject) []string {
+	if obj == nil {
+		return nil
+	}
+	keys := append([]string(nil), obj.keys...)
+	sort.Strings(keys)
+	return keys
+}
+
+func runScaffold(args []string) int {
+	if len(args) == 0 {
+		printError("Usage: install-drupal scaffold <updates|files|devcontainer|gitpod|nix|tool-versions|live-reload> [flags]")
+		return 2
+	}
+
+	switch args[0] {
+	case "updates":
+		return runScaffoldUpdates(args[1:])
+	case "files":
+		return runScaffoldFiles(args[1:])
+	case "devcontainer":
+		return runScaffoldDevcontainer(args[1:])
+	case "gitpod":
+		return runScaffoldGitpod(args[1:])
+	case "nix":
+		return runScaffoldNix(args[1:])
+	case "tool-versions":
+		return runScaffoldToolVersions(args[1:])
+	case "live-reload":
+		return runScaffoldLiveReload(args[1:])
+	}
+	printError(fmt.Sprintf("Unknown scaffold target %q", args[0]))
+	return 2
+}
+
+func runScaffoldUpdates(args []string) int {
+	fs := flag.NewFlagSet("scaffold updates", flag.ContinueOnError)
+	tool := fs.String("tool", "renovate", "Update bot to configure: renovate or dependabot")
+	path := fs.String("path", ".", "Project directory containing composer.json")
+	force := fs.Bool("force", false, "Overwrite an existing configuration file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	composer, err := readJSONFile(filepath.Join(*path, "composer.json"))
+	if err != nil {
+		printError(fmt.Sprintf("Failed to read composer.json: %v", err))
+		return 1
+	}
+
+	devPackages, _ := composer.values["require-dev"].(*jsonObject)
+	var patched *jsonObject
+	if extra, ok := composer.values["extra"].(*jsonObject); ok {
+		patched, _ = extra.values["patches"].(*jsonObject)
+	}
+	data := map[string][]string{
+		"DevPackages":     objectKeys(devPackages),
+		"PatchedPackages": objectKeys(patched),
+	}
+
+	var tmplName, target string
+	switch *tool {
+	case "renovate":
+		tmplName, target = "renovate.json", filepath.Join(*path, "renovate.json")
+	case "dependabot":
+		tmplName, target = "dependabot.yml", filepath.Join(*path, ".github", "dependabot.yml")
+	default:
+		printError(fmt.Sprintf("Unknown update tool %q (expected renovate or dependabot)", *tool))
+		return 2
+	}
+
+	content, err := renderScaffold(tmplName, data)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to render %s: %v", tmplName, err))
+		return 1
+	}
+	if err := writeScaffoldFile(target, content, *force); err != nil {
+		return 1
+	}
+	return 0
+}
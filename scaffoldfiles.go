@@ -0,0 +1,156 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type scaffoldSettings struct {
+	Keep  []string                `yaml:"keep"`
+	Files map[string]scaffoldFile `yaml:"files"`
+}
+
+type scaffoldFile struct {
+	Path      string `yaml:"path"`
+	Append    string `yaml:"append"`
+	Prepend   string `yaml:"prepend"`
+	Overwrite *bool  `yaml:"overwrite"`
+}
+
+func (s scaffoldSettings) empty() bool {
+	return len(s.Keep) == 0 && len(s.Files) == 0
+}
+
+func scaffoldDestination(file string) string {
+	if strings.HasPrefix(file, "[") {
+		return file
+	}
+	return "[web-root]/" + strings.TrimPrefix(file, "/")
+}
+
+func (f scaffoldFile) sources() []string {
+	var sources []string
+	for _, s := range []string{f.Path, f.Append, f.Prepend} {
+		if s != "" {
+			sources = append(sources, s)
+		}
+	}
+	return sources
+}
+
+func (f scaffoldFile) mapping() (any, error) {
+	if f.Path != "" && (f.Append != "" || f.Prepend != "") {
+		return nil, fmt.Errorf("path cannot be combined with append or prepend")
+	}
+	if len(f.sources()) == 0 {
+		return nil, fmt.Errorf("expected path, append or prepend")
+	}
+	if f.Path != "" && f.Overwrite == nil {
+		return f.Path, nil
+	}
+	obj := newJSONObject()
+	if f.Path != "" {
+		obj.set("path", f.Path)
+	}
+	if f.Prepend != "" {
+		obj.set("prepend", f.Prepend)
+	}
+	if f.Append != "" {
+		obj.set("append", f.Append)
+	}
+	if f.Overwrite != nil {
+		obj.set("overwrite", *f.Overwrite)
+	}
+	return obj, nil
+}
+
+func scaffoldMappings(settings scaffoldSettings, sourceDir string) (*jsonObject, error) {
+	mappings := newJSONObject()
+	for _, file := range settings.Keep {
+		mappings.set(scaffoldDestination(file), false)
+	}
+	for _, file := range sortedKeys(settings.Files) {
+		f := settings.Files[file]
+		value, err := f.mapping()
+		if err != nil {
+			return nil, fmt.Errorf("scaffold file %s: %v", file, err)
+		}
+		for _, source := range f.sources() {
+			if _, err := os.Stat(filepath.Join(sourceDir, filepath.FromSlash(source))); err != nil {
+				return nil, fmt.Errorf("scaffold file %s: source %s not found next to drupal-scripts.yml", file, source)
+			}
+		}
+		mappings.set(scaffoldDestination(file), value)
+	}
+	return mappings, nil
+}
+
+func applyScaffoldMappings(projectPath, sourceDir string, settings scaffoldSettings) error {
+	if settings.empty() {
+		return nil
+	}
+	mappings, err := scaffoldMappings(settings, sourceDir)
+	if err != nil {
+		printError(err.Error())
+		return err
+	}
+
+	for _, file := range sortedKeys(settings.Files) {
+		for _, source := range settings.Files[file].sources() {
+			target := filepath.Join(projectPath, filepath.FromSlash(source))
+			if _, err := os.Stat(target); err == nil {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(sourceDir, filepath.FromSlash(source)))
+			if err != nil {
+				return err
+			}
+			if err := writeFile(target, data); err != nil {
+				printError(fmt.Sprintf("Failed to copy %s into the project", source))
+				return err
+			}
+		}
+	}
+
+	composerPath := filepath.Join(projectPath, "composer.json")
+	composer, err := readJSONFile(composerPath)
+	if err != nil {
+		printError("Failed to read composer.json")
+		return err
+	}
+	mergeJSONObject(composer.object("extra").object("drupal-scaffold").object("file-mapping"), mappings)
+	if err := writeJSONFile(composerPath, composer); err != nil {
+		printError("Failed to write composer.json")
+		return err
+	}
+	printSuccess(fmt.Sprintf("✓ drupal-scaffold mappings set for %s", strings.Join(mappings.keys, ", ")))
+	return nil
+}
+
+func runScaffoldFiles(args []string) int {
+	fs := flag.NewFlagSet("scaffold files", flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the project")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if err := loadProjectConfigFrom(projectPath); err != nil {
+		return 1
+	}
+	if project.Scaffold.empty() {
+		printWarning("No scaffold section in drupal-scripts.yml; nothing to do")
+		return 0
+	}
+	if err := applyScaffoldMappings(projectPath, projectPath, project.Scaffold); err != nil {
+		return 1
+	}
+	fmt.Println("Run 'ddev composer drupal:scaffold' to rebuild the scaffold files with the new mappings.")
+	return 0
+}
@@ -0,0 +1,22 @@
+package main
+
+func init() {
+	registerPreset(&preset{
+		name:        "search",
+		description: "Search API with the database backend, a content index and a search page at /search/site",
+		packages:    []string{"drupal/search_api"},
+		modules:     []string{"search_api", "search_api_db"},
+		tour: []tourStop{
+			{path: "/search/site?keys=drupal", label: "Site search (Search API, database backend)"},
+			{path: "/admin/config/search/search-api", label: "Search API servers and indexes"},
+		},
+		apply: indexContent,
+	})
+}
+
+func indexContent(projectPath string) error {
+	if err := runDDEVQuiet(projectPath, "drush", "search-api:index"); err != nil {
+		printWarning("Failed to index existing content; run 'ddev drush search-api:index' later")
+	}
+	return nil
+}
@@ -0,0 +1,334 @@
+package main
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var apiProjectNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+const finishedJobRetention = 24 * time.Hour
+
+const maxAPIBodyBytes = 1 << 20
+
+type serveJob struct {
+	ID       string
+	Action   string
+	Project  string
+	Status   string
+	Error    string
+	Started  time.Time
+	Finished time.Time
+
+	mu  sync.Mutex
+	log bytes.Buffer
+}
+
+func (j *serveJob) Write(p []byte) (int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.log.Write(p)
+}
+
+func (j *serveJob) snapshot() map[string]any {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return map[string]any{
+		"id": j.ID, "action": j.Action, "project": j.Project, "status": j.Status,
+		"error": j.Error, "started": j.Started, "finished": j.Finished, "log": j.log.String(),
+	}
+}
+
+type apiServer struct {
+	baseDir string
+	token   string
+	exe     string
+
+	mu      sync.Mutex
+	jobs    map[string]*serveJob
+	running map[string]bool
+	nextID  int
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, msg string) {
+	writeAPIJSON(w, status, map[string]string{"error": msg})
+}
+
+func (s *apiServer) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+			writeAPIError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *apiServer) startJob(action, project string, fn func(job *serveJob) error) (*serveJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.running[project] {
+		return nil, false
+	}
+	s.pruneJobs(time.Now())
+	s.nextID++
+	job := &serveJob{ID: fmt.Sprintf("%d", s.nextID), Action: action, Project: project, Status: "running", Started: time.Now()}
+	s.jobs[job.ID] = job
+	s.running[project] = true
+
+	go func() {
+		err := fn(job)
+		job.mu.Lock()
+		job.Finished = time.Now()
+		job.Status = "succeeded"
+		if err != nil {
+			job.Status = "failed"
+			job.Error = err.Error()
+		}
+		job.mu.Unlock()
+		s.mu.Lock()
+		delete(s.running, project)
+		s.mu.Unlock()
+		sendWebhooks("serve", fmt.Sprintf("%s %s %s", action, project, job.Status), fmt.Sprintf("Job %s took %s", job.ID, job.Finished.Sub(job.Started).Round(time.Second)))
+	}()
+	return job, true
+}
+
+func (s *apiServer) pruneJobs(now time.Time) {
+	for id, job := range s.jobs {
+		job.mu.Lock()
+		expired := !job.Finished.IsZero() && now.Sub(job.Finished) > finishedJobRetention
+		job.mu.Unlock()
+		if expired {
+			delete(s.jobs, id)
+		}
+	}
+}
+
+func (s *apiServer) projectByName(w http.ResponseWriter, r *http.Request) (managedProject, bool) {
+	project, ok := findRegisteredProjectByName(r.PathValue("name"))
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "unknown project")
+	}
+	return project, ok
+}
+
+func (s *apiServer) handleList(w http.ResponseWriter, r *http.Request) {
+	projects, err := loadRegistry()
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if projects == nil {
+		projects = []managedProject{}
+	}
+	writeAPIJSON(w, http.StatusOK, projects)
+}
+
+func (s *apiServer) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Name    string   `json:"name"`
+		Presets []string `json:"presets"`
+		Quick   bool     `json:"quick"`
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxAPIBodyBytes)
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeAPIError(w, http.StatusRequestEntityTooLarge, "request body too large")
+			return
+		}
+		writeAPIError(w, http.StatusBadRequest, "invalid JSON body")
+		return
+	}
+	if !apiProjectNamePattern.MatchString(req.Name) {
+		writeAPIError(w, http.StatusBadRequest, "name must be lowercase letters, digits and dashes")
+		return
+	}
+	if _, err := os.Stat(filepath.Join(s.baseDir, req.Name)); err == nil {
+		writeAPIError(w, http.StatusConflict, "project directory already exists")
+		return
+	}
+
+	args := []string{"--project-name", req.Name, "--path", s.baseDir, "--notify=false"}
+	for _, p := range req.Presets {
+		args = append(args, "--preset", p)
+	}
+	if req.Quick {
+		args = append(args, "--quick")
+	}
+	job, ok := s.startJob("create", req.Name, func(job *serveJob) error {
+		cmd := exec.Command(s.exe, args...)
+		cmd.Dir = s.baseDir
+		cmd.Stdout, cmd.Stderr = job, job
+		cmd.Env = append(os.Environ(), "NO_COLOR=1")
+		return cmd.Run()
+	})
+	if !ok {
+		writeAPIError(w, http.StatusConflict, "a job is already running for this project")
+		return
+	}
+	writeAPIJSON(w, http.StatusAccepted, job.snapshot())
+}
+
+func (s *apiServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.projectByName(w, r)
+	if !ok {
+		return
+	}
+	status := map[string]any{"project": project, "healthy": projectHealthy(project.URL)}
+	if describe, err := ddevDescribe(project.Path); err == nil {
+		status["ddev_status"] = describe["status"]
+	}
+	writeAPIJSON(w, http.StatusOK, status)
+}
+
+func (s *apiServer) handleDestroy(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.projectByName(w, r)
+	if !ok {
+		return
+	}
+	if filepath.Dir(project.Path) != s.baseDir {
+		writeAPIError(w, http.StatusForbidden, "project is outside the server's base directory")
+		return
+	}
+	job, ok := s.startJob("destroy", project.Name, func(job *serveJob) error {
+		cmd := exec.Command("ddev", "delete", "--omit-snapshot", "--yes")
+		cmd.Dir = project.Path
+		cmd.Stdout, cmd.Stderr = job, job
+		if err := cmd.Run(); err != nil {
+			return err
+		}
+		if err := os.RemoveAll(project.Path); err != nil {
+			return err
+		}
+		return unregisterProject(project.Path)
+	})
+	if !ok {
+		writeAPIError(w, http.StatusConflict, "a job is already running for this project")
+		return
+	}
+	writeAPIJSON(w, http.StatusAccepted, job.snapshot())
+}
+
+func (s *apiServer) handleSnapshot(w http.ResponseWriter, r *http.Request) {
+	project, ok := s.projectByName(w, r)
+	if !ok {
+		return
+	}
+	name := project.Name + "-" + time.Now().Format("20060102-150405")
+	job, ok := s.startJob("snapshot", project.Name, func(job *serveJob) error {
+		cmd := exec.Command("ddev", "snapshot", "--name="+name)
+		cmd.Dir = project.Path
+		cmd.Stdout, cmd.Stderr = job, job
+		return cmd.Run()
+	})
+	if !ok {
+		writeAPIError(w, http.StatusConflict, "a job is already running for this project")
+		return
+	}
+	writeAPIJSON(w, http.StatusAccepted, job.snapshot())
+}
+
+func (s *apiServer) handleJobs(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	jobs := make([]map[string]any, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		snap := job.snapshot()
+		delete(snap, "log")
+		jobs = append(jobs, snap)
+	}
+	s.mu.Unlock()
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i]["started"].(time.Time).Before(jobs[j]["started"].(time.Time)) })
+	writeAPIJSON(w, http.StatusOK, jobs)
+}
+
+func (s *apiServer) handleJob(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	job, ok := s.jobs[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		writeAPIError(w, http.StatusNotFound, "unknown job")
+		return
+	}
+	writeAPIJSON(w, http.StatusOK, job.snapshot())
+}
+
+func (s *apiServer) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /projects", s.handleList)
+	mux.HandleFunc("POST /projects", s.handleCreate)
+	mux.HandleFunc("GET /projects/{name}", s.handleStatus)
+	mux.HandleFunc("DELETE /projects/{name}", s.handleDestroy)
+	mux.HandleFunc("POST /projects/{name}/snapshots", s.handleSnapshot)
+	mux.HandleFunc("GET /jobs", s.handleJobs)
+	mux.HandleFunc("GET /jobs/{id}", s.handleJob)
+	return s.authenticate(mux)
+}
+
+func runServe(args []string) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listen := fs.String("listen", "127.0.0.1:8787", "Address to listen on")
+	dir := fs.String("dir", ".", "Directory new projects are created in")
+	token := fs.String("token", os.Getenv("DRUPAL_SCRIPTS_API_TOKEN"), "Bearer token clients must send (default: $DRUPAL_SCRIPTS_API_TOKEN, or generated)")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	baseDir, err := filepath.Abs(*dir)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		printError(fmt.Sprintf("Cannot locate the installer binary: %v", err))
+		return 1
+	}
+	if *token == "" {
+		generated, err := randomString(apr1Alphabet[2:], 40)
+		if err != nil {
+			printError(err.Error())
+			return 1
+		}
+		*token = generated
+		printStatus("Generated API token (set DRUPAL_SCRIPTS_API_TOKEN to keep it across restarts):")
+		fmt.Println(*token)
+	}
+
+	server := &apiServer{baseDir: baseDir, token: *token, exe: exe, jobs: map[string]*serveJob{}, running: map[string]bool{}}
+	printSuccess(fmt.Sprintf("Serving the project API on http://%s (projects in %s)", *listen, baseDir))
+	httpServer := &http.Server{
+		Addr:              *listen,
+		Handler:           server.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	if err := httpServer.ListenAndServe(); err != nil {
+		printError(err.Error())
+		return 1
+	}
+	return 0
+}
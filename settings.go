@@ -0,0 +1,274 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type settingsFile struct {
+	path  string
+	lines []string
+}
+
+var (
+	settingsEntryPattern = regexp.MustCompile(`^\s*\$(settings|config|databases)((?:\[\s*(?:'[^']*'|"[^"]*")\s*\])+)\s*=[^=>]`)
+	settingsIndexPattern = regexp.MustCompile(`\[\s*(?:'([^']*)'|"([^"]*)")\s*\]`)
+)
+
+func settingsPHPPath(projectPath string) string {
+	return filepath.Join(siteDefaultDir(projectPath), "settings.php")
+}
+
+func openSettingsFile(path string) (*settingsFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(string(data), "#ddev-generated") {
+		return nil, fmt.Errorf("%s is generated by DDEV and would be overwritten; edit settings.php instead", filepath.Base(path))
+	}
+	return &settingsFile{path: path, lines: strings.Split(string(data), "\n")}, nil
+}
+
+func (f *settingsFile) save() error {
+	return writeFile(f.path, []byte(strings.Join(f.lines, "\n")))
+}
+
+func settingsEntryKey(variable, index string) string {
+	key := "$" + variable
+	for _, m := range settingsIndexPattern.FindAllStringSubmatch(index, -1) {
+		key += "['" + m[1] + m[2] + "']"
+	}
+	return key
+}
+
+func (f *settingsFile) statementEnd(start int) int {
+	depth := 0
+	var quote rune
+	for i := start; i < len(f.lines); i++ {
+		escaped := false
+		for _, r := range f.lines[i] {
+			switch {
+			case escaped:
+				escaped = false
+			case quote != 0:
+				if r == '\\' {
+					escaped = true
+				} else if r == quote {
+					quote = 0
+				}
+			case r == '\'' || r == '"':
+				quote = r
+			case r == '[' || r == '(':
+				depth++
+			case r == ']' || r == ')':
+				depth--
+			case r == ';' && depth == 0:
+				return i
+			}
+		}
+	}
+	return len(f.lines) - 1
+}
+
+func (f *settingsFile) find(key string) [][2]int {
+	want := strings.ReplaceAll(key, `"`, "'")
+	if m := settingsEntryPattern.FindStringSubmatch(want + " = x"); m != nil {
+		want = settingsEntryKey(m[1], m[2])
+	}
+	var found [][2]int
+	for i := 0; i < len(f.lines); i++ {
+		m := settingsEntryPattern.FindStringSubmatch(f.lines[i])
+		if m == nil || settingsEntryKey(m[1], m[2]) != want {
+			continue
+		}
+		end := f.statementEnd(i)
+		found = append(found, [2]int{i, end})
+		i = end
+	}
+	return found
+}
+
+func (f *settingsFile) set(key, value string) bool {
+	line := key + " = " + value + ";"
+	found := f.find(key)
+	if len(found) == 0 {
+		f.append("", line)
+		return true
+	}
+	last := found[len(found)-1]
+	start := f.lines[last[0]]
+	line = start[:len(start)-len(strings.TrimLeft(start, " \t"))] + line
+	if last[0] == last[1] && f.lines[last[0]] == line {
+		return false
+	}
+	f.lines = append(f.lines[:last[0]], append([]string{line}, f.lines[last[1]+1:]...)...)
+	return true
+}
+
+func (f *settingsFile) remove(key string) int {
+	found := f.find(key)
+	for i := len(found) - 1; i >= 0; i-- {
+		f.lines = append(f.lines[:found[i][0]], f.lines[found[i][1]+1:]...)
+	}
+	return len(found)
+}
+
+func (f *settingsFile) append(comment, code string) bool {
+	if strings.Contains(strings.Join(f.lines, "\n"), code) {
+		return false
+	}
+	for len(f.lines) > 0 && strings.TrimSpace(f.lines[len(f.lines)-1]) == "" {
+		f.lines = f.lines[:len(f.lines)-1]
+	}
+	f.lines = append(f.lines, "")
+	if comment != "" {
+		f.lines = append(f.lines, "// "+comment)
+	}
+	f.lines = append(f.lines, strings.Split(code, "\n")...)
+	f.lines = append(f.lines, "")
+	return true
+}
+
+func (f *settingsFile) prepend(comment, code string) (bool, error) {
+	if strings.Contains(strings.Join(f.lines, "\n"), code) {
+		return false, nil
+	}
+	for i, line := range f.lines {
+		if strings.TrimSpace(line) != "<?php" {
+			continue
+		}
+		block := append([]string{"", "// " + comment}, strings.Split(code, "\n")...)
+		f.lines = append(f.lines[:i+1], append(block, f.lines[i+1:]...)...)
+		return true, nil
+	}
+	return false, fmt.Errorf("%s does not start with <?php", filepath.Base(f.path))
+}
+
+func phpString(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+}
+
+func editSettings(projectPath string, edit func(f *settingsFile) (bool, error)) error {
+	f, err := openSettingsFile(settingsPHPPath(projectPath))
+	if err != nil {
+		printError("Failed to read settings.php")
+		return err
+	}
+	changed, err := edit(f)
+	if err != nil || !changed {
+		return err
+	}
+	if err := f.save(); err != nil {
+		printError("Failed to update settings.php")
+		return err
+	}
+	return nil
+}
+
+func appendToSettings(projectPath, comment, code string) error {
+	return editSettings(projectPath, func(f *settingsFile) (bool, error) {
+		return f.append(comment, code), nil
+	})
+}
+
+func prependToSettings(projectPath, comment, code string) error {
+	return editSettings(projectPath, func(f *settingsFile) (bool, error) {
+		return f.prepend(comment, code)
+	})
+}
+
+//go:embed settings/*.php
+var embeddedSettingsFiles embed.FS
+
+const settingsChain = `$drupal_environment = getenv('DRUPAL_ENVIRONMENT') ?: (getenv('IS_DDEV_PROJECT') == 'true' ? 'local' : 'prod');
+if ($drupal_environment !== 'local' && file_exists(__DIR__ . '/settings.' . $drupal_environment . '.php')) {
+  include __DIR__ . '/settings.' . $drupal_environment . '.php';
+}
+if (file_exists(__DIR__ . '/settings.local.php')) {
+  include __DIR__ . '/settings.local.php';
+}`
+
+const noindexInclude = `if ($drupal_environment !== 'prod' && file_exists(__DIR__ . '/noindex.settings.php')) {
+  include __DIR__ . '/noindex.settings.php';
+}`
+
+const ddevGeneratedMarker = "#ddev-generated"
+
+func claimDDEVSettings(projectPath string) error {
+	settingsPath := settingsPHPPath(projectPath)
+	data, err := os.ReadFile(settingsPath)
+	if err != nil {
+		printError("Failed to read settings.php")
+		return err
+	}
+	if strings.Contains(string(data), ddevGeneratedMarker) {
+		var kept []string
+		for _, line := range strings.Split(string(data), "\n") {
+			if !strings.Contains(line, ddevGeneratedMarker) {
+				kept = append(kept, line)
+			}
+		}
+		if err := writeFile(settingsPath, []byte(strings.Join(kept, "\n"))); err != nil {
+			printError("Failed to update settings.php")
+			return err
+		}
+		printStatus("Took over settings.php from DDEV so 'ddev start' no longer regenerates it")
+	}
+
+	ddevPath := filepath.Join(siteDefaultDir(projectPath), "settings.ddev.php")
+	data, err = os.ReadFile(ddevPath)
+	if err != nil {
+		return nil
+	}
+	if !strings.Contains(string(data), ddevGeneratedMarker) {
+		printWarning("settings.ddev.php has no #ddev-generated marker, so DDEV no longer updates it; move your changes to settings.php and restore the marker")
+	}
+	return nil
+}
+
+func writeSettingsChain(projectPath string) error {
+	siteDir := siteDefaultDir(projectPath)
+	if err := claimDDEVSettings(projectPath); err != nil {
+		return err
+	}
+	entries, err := fs.ReadDir(embeddedSettingsFiles, "settings")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		dest := filepath.Join(siteDir, entry.Name())
+		if _, err := os.Stat(dest); err == nil {
+			continue
+		}
+		data, err := fs.ReadFile(embeddedSettingsFiles, "settings/"+entry.Name())
+		if err != nil {
+			return err
+		}
+		if err := writeFile(dest, data); err != nil {
+			printError(fmt.Sprintf("Failed to write %s", entry.Name()))
+			return err
+		}
+	}
+
+	if err := editSettings(projectPath, func(f *settingsFile) (bool, error) {
+		return f.set("$settings['config_sync_directory']", phpString("../config/sync")), nil
+	}); err != nil {
+		return err
+	}
+	if err := appendToSettings(projectPath, "Environment settings chain: settings.<env>.php, then settings.local.php (drupal-scripts).", settingsChain); err != nil {
+		return err
+	}
+	if err := appendToSettings(projectPath, "Keep non-production environments out of search engines (drupal-scripts).", noindexInclude); err != nil {
+		return err
+	}
+	if err := ensureGitignored(projectPath, siteDefaultFile(projectPath, "settings.local.php")); err != nil {
+		printWarning(fmt.Sprintf("Could not add settings.local.php to .gitignore: %v", err))
+	}
+	return nil
+}
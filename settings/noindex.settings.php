@@ -0,0 +1,18 @@
+<?php
+
+/**
+ * @file
+ * Keeps non-production environments out of search engines.
+ *
+ * Included by settings.php for every environment except prod, the same
+ * environments whose indicator is not "Production". Delete the include in
+ * settings.php to let an environment be indexed.
+ */
+
+// Ask crawlers not to index or follow any page Drupal serves.
+if (PHP_SAPI !== 'cli') {
+  header('X-Robots-Tag: noindex, nofollow');
+}
+
+// Override the robots meta tag when the Metatag module is enabled.
+$config['metatag.metatag_defaults.global']['tags']['robots'] = 'noindex, nofollow';
@@ -0,0 +1,11 @@
+<?php
+
+/**
+ * @file
+ * Settings for the shared development environment (DRUPAL_ENVIRONMENT=dev).
+ */
+
+$config['config_split.config_split.local']['status'] = FALSE;
+$config['environment_indicator.indicator']['name'] = 'Development';
+$config['environment_indicator.indicator']['bg_color'] = '#1a6a3a';
+$config['environment_indicator.indicator']['fg_color'] = '#ffffff';
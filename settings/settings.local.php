@@ -0,0 +1,17 @@
+<?php
+
+/**
+ * @file
+ * Settings for this machine only; not committed.
+ *
+ * Included last by settings.php, after the environment's settings file.
+ */
+
+// Development-only modules live in the "local" config split.
+$config['config_split.config_split.local']['status'] = TRUE;
+
+// Show all errors.
+$config['system.logging']['error_level'] = 'verbose';
+
+// Skip file permission hardening so DDEV can write to sites/default.
+$settings['skip_permissions_hardening'] = TRUE;
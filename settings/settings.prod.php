@@ -0,0 +1,12 @@
+<?php
+
+/**
+ * @file
+ * Settings for production (DRUPAL_ENVIRONMENT=prod).
+ */
+
+$config['config_split.config_split.local']['status'] = FALSE;
+$config['environment_indicator.indicator']['name'] = 'Production';
+$config['environment_indicator.indicator']['bg_color'] = '#a51b00';
+$config['environment_indicator.indicator']['fg_color'] = '#ffffff';
+$config['system.logging']['error_level'] = 'hide';
@@ -0,0 +1,11 @@
+<?php
+
+/**
+ * @file
+ * Settings for the staging environment (DRUPAL_ENVIRONMENT=stage).
+ */
+
+$config['config_split.config_split.local']['status'] = FALSE;
+$config['environment_indicator.indicator']['name'] = 'Staging';
+$config['environment_indicator.indicator']['bg_color'] = '#b36b00';
+$config['environment_indicator.indicator']['fg_color'] = '#ffffff';
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type sharedServerSettings struct {
+	Domain string `yaml:"domain"`
+	Root   string `yaml:"root"`
+}
+
+const (
+	systemConfigPath  = "/etc/drupal-scripts/config.yml"
+	defaultSharedRoot = "/srv/drupal"
+)
+
+var sharedServer sharedServerSettings
+
+var sharedNameReplacer = regexp.MustCompile(`[^a-z0-9]+`)
+
+func loadSharedServer() error {
+	settings, err := loadUserSettings()
+	if err != nil {
+		printError(err.Error())
+		return err
+	}
+	sharedServer = settings.SharedServer
+	if opts.sharedDomain != "" {
+		sharedServer.Domain = opts.sharedDomain
+	}
+	if opts.sharedRoot != "" {
+		sharedServer.Root = opts.sharedRoot
+	}
+	sharedServer.Domain = strings.Trim(strings.ToLower(sharedServer.Domain), ".")
+	if sharedServer.Root == "" {
+		sharedServer.Root = defaultSharedRoot
+	}
+	if sharedMode() {
+		printStatus(fmt.Sprintf("Shared server mode: projects in %s, served at *.%s", sharedProjectsDir(), sharedServer.Domain))
+	}
+	return nil
+}
+
+func sharedMode() bool {
+	return sharedServer.Domain != ""
+}
+
+func sharedUser() string {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	return strings.Trim(sharedNameReplacer.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+func sharedProjectsDir() string {
+	return filepath.Join(sharedServer.Root, sharedUser())
+}
+
+func ensureSharedProjectsDir() (string, error) {
+	dir := sharedProjectsDir()
+	if dryRunFile("create directory (mode 0700)", dir) {
+		return dir, nil
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("cannot create %s (the server admin must create %s writable by all users, e.g. mode 1777): %v", dir, sharedServer.Root, err)
+	}
+	if err := os.Chmod(dir, 0700); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+func ddevProjectName(projectPath string) string {
+	name := filepath.Base(projectPath)
+	if sharedMode() {
+		return name + "-" + sharedUser()
+	}
+	return name
+}
+
+func sharedHostname(projectPath string) string {
+	return ddevProjectName(projectPath) + "." + sharedServer.Domain
+}
+
+func warnSharedRouter() {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return
+	}
+	data, _ := os.ReadFile(filepath.Join(home, ".ddev", "global_config.yaml"))
+	if !strings.Contains(string(data), "router_bind_all_interfaces: true") {
+		printWarning("DDEV's router only listens on localhost; run 'ddev config global --router-bind-all-interfaces' so teammates can reach your sites")
+	}
+}
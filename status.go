@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	requirementWarning = 1
+	requirementError   = 2
+)
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+type requirement struct {
+	ID          string
+	Title       string `json:"title"`
+	Value       any    `json:"value"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	SID         any    `json:"sid"`
+}
+
+func (r requirement) level() int {
+	switch v := r.SID.(type) {
+	case float64:
+		return int(v)
+	case string:
+		var n int
+		fmt.Sscan(v, &n)
+		return n
+	}
+	if strings.EqualFold(r.Severity, "error") {
+		return requirementError
+	}
+	return requirementWarning
+}
+
+func plainText(s string) string {
+	s = htmlTagPattern.ReplaceAllString(s, "")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
+
+func siteRequirements(projectPath string) ([]requirement, error) {
+	output, err := ddevOutput(projectPath, "drush", "core:requirements", "--severity=1", "--format=json")
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string]requirement
+	if trimmed := strings.TrimSpace(string(output)); trimmed != "" && trimmed != "[]" {
+		if err := json.Unmarshal(output, &raw); err != nil {
+			return nil, fmt.Errorf("could not parse drush output: %v", err)
+		}
+	}
+
+	reqs := make([]requirement, 0, len(raw))
+	for id, r := range raw {
+		r.ID = id
+		reqs = append(reqs, r)
+	}
+	sort.Slice(reqs, func(i, j int) bool {
+		if reqs[i].level() != reqs[j].level() {
+			return reqs[i].level() > reqs[j].level()
+		}
+		return reqs[i].Title < reqs[j].Title
+	})
+	return reqs, nil
+}
+
+func printRequirement(r requirement) {
+	msg := plainText(r.Title)
+	if value := plainText(fmt.Sprint(r.Value)); r.Value != nil && value != "" {
+		msg += ": " + value
+	}
+	if r.level() >= requirementError {
+		printError(msg)
+	} else {
+		printWarning(msg)
+	}
+	if desc := plainText(r.Description); desc != "" {
+		fmt.Printf("    %s\n", desc)
+	}
+}
+
+func runStatus(args []string) int {
+	fs := flag.NewFlagSet("status", flag.ContinueOnError)
+	path := fs.String("path", ".", "Path to the DDEV project")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	projectPath, err := filepath.Abs(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	if _, err := os.Stat(filepath.Join(projectPath, ".ddev", "config.yaml")); err != nil {
+		printError(fmt.Sprintf("%s is not a DDEV project", projectPath))
+		return 1
+	}
+
+	printStatus("Checking the Drupal status report...")
+	reqs, err := siteRequirements(projectPath)
+	if err != nil {
+		printError(fmt.Sprintf("Failed to read the status report: %v", err))
+		return 1
+	}
+	if len(reqs) == 0 {
+		printSuccess("✓ No warnings or errors in the status report")
+		return 0
+	}
+
+	errors := 0
+	for _, r := range reqs {
+		printRequirement(r)
+		if r.level() >= requirementError {
+			errors++
+		}
+	}
+	fmt.Println()
+	fmt.Printf("%d errors, %d warnings\n", errors, len(reqs)-errors)
+	if errors > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type artifactVerification struct {
+	Artifact  string    `json:"artifact"`
+	Source    string    `json:"source"`
+	Method    string    `json:"method"`
+	Expected  string    `json:"expected,omitempty"`
+	Actual    string    `json:"actual"`
+	Verified  bool      `json:"verified"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+const maxVerificationRecords = 200
+
+func verificationsPath() (string, error) {
+	dir, err := userConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "verifications.json"), nil
+}
+
+func recordVerification(v artifactVerification) {
+	path, err := verificationsPath()
+	if err != nil {
+		return
+	}
+	var records []artifactVerification
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &records)
+	}
+	v.CheckedAt = time.Now()
+	records = append(records, v)
+	if len(records) > maxVerificationRecords {
+		records = records[len(records)-maxVerificationRecords:]
+	}
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := writeFile(path, append(data, '\n')); err != nil {
+		printWarning(fmt.Sprintf("Could not record verification of %s: %v", v.Artifact, err))
+	}
+}
+
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func sha256Bytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+type brewInfo struct {
+	Formulae []struct {
+		URLs struct {
+			Stable struct {
+				URL      string `json:"url"`
+				Checksum string `json:"checksum"`
+			} `json:"stable"`
+		} `json:"urls"`
+		Bottle struct {
+			Stable struct {
+				Files map[string]struct {
+					URL    string `json:"url"`
+					SHA256 string `json:"sha256"`
+				} `json:"files"`
+			} `json:"stable"`
+		} `json:"bottle"`
+	} `json:"formulae"`
+	Casks []struct {
+		URL    string `json:"url"`
+		SHA256 string `json:"sha256"`
+	} `json:"casks"`
+}
+
+func verifyBrewArtifact(name string) error {
+	output, err := exec.Command("brew", "info", "--json=v2", name).Output()
+	if err != nil {
+		return fmt.Errorf("brew info %s: %v", name, err)
+	}
+	var info brewInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		return fmt.Errorf("brew info %s: %v", name, err)
+	}
+
+	expected := map[string]string{}
+	for _, f := range info.Formulae {
+		for _, file := range f.Bottle.Stable.Files {
+			expected[file.SHA256] = file.URL
+		}
+		if f.URLs.Stable.Checksum != "" {
+			expected[f.URLs.Stable.Checksum] = f.URLs.Stable.URL
+		}
+	}
+	for _, c := range info.Casks {
+		if c.SHA256 == "no_check" {
+			printWarning(fmt.Sprintf("%s does not publish a checksum; its download could not be checked", name))
+			recordVerification(artifactVerification{Artifact: name, Source: c.URL, Method: "sha256 consistency with brew metadata (no independent pin, dependencies unchecked)", Actual: "no_check"})
+			return nil
+		}
+		expected[c.SHA256] = c.URL
+	}
+	if len(expected) == 0 {
+		return fmt.Errorf("no published checksum found for %s", name)
+	}
+
+	cached, err := runCommandOutput("brew", "--cache", name)
+	if err != nil {
+		return fmt.Errorf("brew --cache %s: %v", name, err)
+	}
+	cachePath := strings.TrimSpace(cached)
+	actual, err := sha256File(cachePath)
+	if err != nil {
+		return err
+	}
+
+	source, verified := expected[actual]
+	record := artifactVerification{Artifact: name, Source: source, Method: "sha256 consistency with brew metadata (no independent pin, dependencies unchecked)", Actual: actual, Verified: verified}
+	if !verified {
+		var sums []string
+		for sum := range expected {
+			sums = append(sums, sum)
+		}
+		record.Source = cachePath
+		record.Expected = strings.Join(sums, ",")
+	}
+	recordVerification(record)
+
+	if !verified {
+		return fmt.Errorf("checksum of %s (%s) matches none of the checksums in the brew metadata", filepath.Base(cachePath), actual)
+	}
+	printSuccess(fmt.Sprintf("✓ %s download matches its brew metadata (sha256 %s…)", name, actual[:12]))
+	return nil
+}
+
+func brewInstallVerified(action, name string) error {
+	if err := runCommand("brew", "fetch", name); err != nil {
+		return err
+	}
+	if !opts.dryRun {
+		if err := verifyBrewArtifact(name); err != nil {
+			printError(fmt.Sprintf("Supply-chain check failed for %s: %v", name, err))
+			return err
+		}
+	}
+	return runCommand("brew", action, name)
+}
+
+func verifyDownload(artifact, source string, data []byte, expected string) error {
+	actual := sha256Bytes(data)
+	expected = strings.ToLower(strings.TrimSpace(expected))
+	record := artifactVerification{Artifact: artifact, Source: source, Actual: actual, Expected: expected}
+	if expected == "" {
+		record.Method = "sha256 recorded (no expected checksum given)"
+		recordVerification(record)
+		return nil
+	}
+	record.Method = "sha256 pin"
+	record.Verified = actual == expected
+	recordVerification(record)
+	if !record.Verified {
+		return fmt.Errorf("sha256 of %s is %s, expected %s", source, actual, expected)
+	}
+	printSuccess(fmt.Sprintf("✓ Verified %s (sha256 %s…)", artifact, actual[:12]))
+	return nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+//go:embed config/*.yml.tmpl
+var embeddedConfigTemplates embed.FS
+
+const configTemplateExt = ".yml.tmpl"
+
+var configTemplates = map[string]string{}
+
+var configTemplateFuncs = template.FuncMap{
+	"yaml": yamlQuote,
+}
+
+func defaultTemplateVars() map[string]string {
+	return map[string]string{
+		"EnvironmentName": "Local DDEV Environment",
+		"FgColor":         "#d783ff",
+		"BgColor":         "#000000",
+		"SplitLabel":      "Local",
+		"SplitFolder":     phpPath(filepath.Join("..", "config", "local")),
+	}
+}
+
+func registerConfigTemplate(name, body string) {
+	configTemplates[name] = body
+}
+
+func loadTemplatesFromFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), configTemplateExt) {
+			continue
+		}
+		body, err := fs.ReadFile(fsys, filepath.ToSlash(filepath.Join(dir, entry.Name())))
+		if err != nil {
+			return err
+		}
+		registerConfigTemplate(strings.TrimSuffix(entry.Name(), configTemplateExt), string(body))
+	}
+	return nil
+}
+
+func loadConfigTemplates() error {
+	if err := loadTemplatesFromFS(embeddedConfigTemplates, "config"); err != nil {
+		return err
+	}
+
+	dir, err := userConfigDir()
+	if err != nil {
+		return nil
+	}
+	userTemplates := filepath.Join(dir, "templates")
+	if _, err := os.Stat(userTemplates); err != nil {
+		return nil
+	}
+	printStatus(fmt.Sprintf("Loading user config templates from %s", userTemplates))
+	return loadTemplatesFromFS(os.DirFS(userTemplates), ".")
+}
+
+func configTemplateNames() []string {
+	names := make([]string, 0, len(configTemplates))
+	for name := range configTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func renderConfigTemplate(name string, vars map[string]string) ([]byte, error) {
+	body, ok := configTemplates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown config template %q", name)
+	}
+	tmpl, err := template.New(name).Funcs(configTemplateFuncs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderConfigTemplates(destDir string, overrides map[string]string) error {
+	vars := defaultTemplateVars()
+	for k, v := range overrides {
+		vars[k] = v
+	}
+
+	for _, name := range configTemplateNames() {
+		data, err := renderConfigTemplate(name, vars)
+		if err != nil {
+			printError(fmt.Sprintf("Failed to render config template %s: %v", name, err))
+			return err
+		}
+		if err := writeFile(filepath.Join(destDir, name+".yml"), data); err != nil {
+			printError(fmt.Sprintf("Failed to write config %s", name))
+			return err
+		}
+	}
+	return nil
+}
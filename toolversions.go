@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type hostTool struct {
+	asdf    string
+	mise    string
+	version string
+}
+
+var (
+	ddevNodeVersionPattern     = regexp.MustCompile(`(?m)^nodejs_version:\s*["']?([0-9.]+)`)
+	ddevComposerVersionPattern = regexp.MustCompile(`(?m)^composer_version:\s*["']?([0-9.]+)`)
+	exactVersionPattern        = regexp.MustCompile(`\d+\.\d+\.\d+`)
+)
+
+func containerVersion(projectPath string, command ...string) string {
+	cmd := ddevCommand(projectPath, append([]string{"exec"}, command...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return exactVersionPattern.FindString(string(output))
+}
+
+func projectToolVersions(projectPath string) ([]hostTool, bool) {
+	config, _ := os.ReadFile(filepath.Join(projectPath, ".ddev", "config.yaml"))
+	configured := func(pattern *regexp.Regexp, fallback string) string {
+		if m := pattern.FindSubmatch(config); m != nil {
+			return string(m[1])
+		}
+		return fallback
+	}
+	tools := []hostTool{
+		{asdf: "php", mise: "php", version: projectPHPVersion(projectPath)},
+		{asdf: "nodejs", mise: "node", version: configured(ddevNodeVersionPattern, "22")},
+		{asdf: "composer", mise: "composer", version: configured(ddevComposerVersionPattern, "2")},
+	}
+	commands := [][]string{
+		{"php", "-r", "echo PHP_VERSION;"},
+		{"node", "--version"},
+		{"composer", "--version", "--no-ansi"},
+	}
+	exact := true
+	for i := range tools {
+		if v := containerVersion(projectPath, commands[i]...); v != "" {
+			tools[i].version = v
+		} else {
+			exact = false
+		}
+	}
+	return tools, exact
+}
+
+func renderToolVersions(tools []hostTool) string {
+	var b strings.Builder
+	for _, t := range tools {
+		fmt.Fprintf(&b, "%s %s\n", t.asdf, t.version)
+	}
+	return b.String()
+}
+
+func renderMiseToml(tools []hostTool) string {
+	var b strings.Builder
+	b.WriteString("# Generated by drupal-scripts from the project's DDEV web container.\n[tools]\n")
+	for _, t := range tools {
+		fmt.Fprintf(&b, "%s = %q\n", t.mise, t.version)
+	}
+	return b.String()
+}
+
+func runScaffoldToolVersions(args []string) int {
+	fs := flag.NewFlagSet("scaffold tool-versions", flag.ContinueOnError)
+	format := fs.String("format", "both", "File to write: asdf (.tool-versions), mise (mise.toml) or both")
+	path := fs.String("path", ".", "Directory inside the project")
+	force := fs.Bool("force", false, "Overwrite existing files")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *format != "asdf" && *format != "mise" && *format != "both" {
+		printError(fmt.Sprintf("Unknown --format %q (expected asdf, mise or both)", *format))
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+
+	tools, exact := projectToolVersions(projectPath)
+	if !exact {
+		printWarning("Could not read every version from the web container (is the project started?); using the versions in .ddev/config.yaml")
+		if *format != "mise" {
+			printWarning("asdf needs exact versions in .tool-versions; start the project with 'ddev start' and re-run with --force")
+		}
+	}
+	if *format != "mise" {
+		if err := writeScaffoldFile(filepath.Join(projectPath, ".tool-versions"), []byte(renderToolVersions(tools)), *force); err != nil {
+			return 1
+		}
+	}
+	if *format != "asdf" {
+		if err := writeScaffoldFile(filepath.Join(projectPath, "mise.toml"), []byte(renderMiseToml(tools)), *force); err != nil {
+			return 1
+		}
+	}
+	var pinned []string
+	for _, t := range tools {
+		pinned = append(pinned, t.mise+" "+t.version)
+	}
+	printStatus(fmt.Sprintf("Pinned %s", strings.Join(pinned, ", ")))
+	return 0
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+type environmentDatabase struct {
+	Driver      string `yaml:"driver"`
+	Host        string `yaml:"host"`
+	Port        int    `yaml:"port"`
+	Name        string `yaml:"name"`
+	User        string `yaml:"user"`
+	Password    string `yaml:"password"`
+	PasswordEnv string `yaml:"password_env"`
+}
+
+const tunnelSettingsFile = "settings.tunnel.php"
+
+const tunnelSettingsInclude = `if (file_exists(__DIR__ . '/settings.tunnel.php')) {
+  include __DIR__ . '/settings.tunnel.php';
+}`
+
+func (d environmentDatabase) password() string {
+	if d.PasswordEnv != "" {
+		return os.Getenv(d.PasswordEnv)
+	}
+	return d.Password
+}
+
+func tunnelSettings(key string, db environmentDatabase, port int) string {
+	return fmt.Sprintf(`<?php
+
+// Generated by 'install-drupal tunnel' and removed when the tunnel closes.
+$databases[%s]['default'] = [
+  'driver' => %s,
+  'host' => '127.0.0.1',
+  'port' => %d,
+  'database' => %s,
+  'username' => %s,
+  'password' => %s,
+  'prefix' => '',
+];
+`, phpString(key), phpString(db.Driver), port, phpString(db.Name), phpString(db.User), phpString(db.password()))
+}
+
+func runTunnel(args []string) int {
+	fs := flag.NewFlagSet("tunnel", flag.ContinueOnError)
+	path := fs.String("path", ".", "Directory inside the local project")
+	port := fs.Int("port", 33306, "Port the tunnel listens on inside the DDEV web container")
+	key := fs.String("key", "migrate", "Key of the $databases entry the remote database is registered under")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	projectPath, err := findProjectRoot(*path)
+	if err != nil {
+		printError(err.Error())
+		return 1
+	}
+	envs, err := projectEnvironments(projectPath)
+	if err != nil {
+		return 1
+	}
+	name := fs.Arg(0)
+	if name == "" {
+		name = currentEnvironment(projectPath)
+	}
+	env, ok := envs[name]
+	if !ok {
+		printError("Usage: install-drupal tunnel [--port N] [--key migrate] <environment> (or select one with 'env use')")
+		return 2
+	}
+	if env.SSH.Host == "" || env.Database.Name == "" {
+		printError(fmt.Sprintf("Environment %s needs ssh.host and database.name in drupal-scripts.yml", name))
+		return 2
+	}
+	db := env.Database
+	db.Driver = firstNonEmpty(db.Driver, "mysql")
+	if db.Port == 0 {
+		db.Port = 3306
+		if db.Driver == "pgsql" {
+			db.Port = 5432
+		}
+	}
+	if db.PasswordEnv != "" && db.password() == "" {
+		printWarning(fmt.Sprintf("%s is not set; connecting without a password", db.PasswordEnv))
+	}
+
+	settingsPath := filepath.Join(siteDefaultDir(projectPath), tunnelSettingsFile)
+	if err := appendToSettings(projectPath, "Database tunnels opened with 'install-drupal tunnel' (drupal-scripts).", tunnelSettingsInclude); err != nil {
+		return 1
+	}
+	if err := ensureGitignored(projectPath, siteDefaultFile(projectPath, tunnelSettingsFile)); err != nil {
+		printWarning(fmt.Sprintf("Could not add %s to .gitignore: %v", tunnelSettingsFile, err))
+	}
+	if err := writeSecretFile(settingsPath, []byte(tunnelSettings(*key, db, *port))); err != nil {
+		printError(err.Error())
+		return 1
+	}
+	defer os.Remove(settingsPath)
+
+	target := env.SSH.Host
+	if env.SSH.User != "" {
+		target = env.SSH.User + "@" + env.SSH.Host
+	}
+	sshArgs := []string{"exec", "ssh", "-N",
+		"-o", "ExitOnForwardFailure=yes", "-o", "ServerAliveInterval=30",
+		"-L", fmt.Sprintf("0.0.0.0:%d:%s:%d", *port, firstNonEmpty(db.Host, "127.0.0.1"), db.Port)}
+	if env.SSH.Port != 0 {
+		sshArgs = append(sshArgs, "-p", strconv.Itoa(env.SSH.Port))
+	}
+	sshArgs = append(sshArgs, target)
+
+	printStatus(fmt.Sprintf("Tunnelling %s's database %s through %s", name, db.Name, target))
+	fmt.Printf("  Drupal:         $databases['%s']['default'] (e.g. migrate source key: %s)\n", *key, *key)
+	fmt.Printf("  DDEV network:   web:%d\n", *port)
+	fmt.Println("Press Ctrl-C to close the tunnel.")
+	if err := runDDEV(projectPath, sshArgs...); err != nil {
+		printError(fmt.Sprintf("The tunnel closed with an error: %v", err))
+		fmt.Println("If ssh could not authenticate, run 'ddev auth ssh' to make your keys available in the container.")
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func userConfigDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".drupal-scripts"), nil
+}
+
+func applyConfigOverrides(destDir string) error {
+	dir, err := userConfigDir()
+	if err != nil {
+		return nil
+	}
+	overridesDir := filepath.Join(dir, "config-overrides")
+	entries, err := os.ReadDir(overridesDir)
+	if err != nil {
+		return nil
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || (filepath.Ext(name) != ".yml" && filepath.Ext(name) != ".yaml") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(overridesDir, name))
+		if err != nil {
+			printError(fmt.Sprintf("Failed to read config override %s", name))
+			return err
+		}
+		target := strings.TrimSuffix(name, filepath.Ext(name)) + ".yml"
+		if err := writeFile(filepath.Join(destDir, target), data); err != nil {
+			printError(fmt.Sprintf("Failed to write config override %s", target))
+			return err
+		}
+		printStatus(fmt.Sprintf("Applied config override %s", target))
+	}
+	return nil
+}
+
+type userSettings struct {
+	Workspace    string                  `yaml:"workspace"`
+	PolicyURL    string                  `yaml:"policy_url"`
+	Telemetry    *bool                   `yaml:"telemetry"`
+	Webhooks     []webhookConfig         `yaml:"webhooks"`
+	SharedServer sharedServerSettings    `yaml:"shared_server"`
+	Archive      archiveStorage          `yaml:"archive"`
+	Defaults     userDefaults            `yaml:"defaults"`
+	Profiles     map[string]userDefaults `yaml:"profiles"`
+}
+
+func loadUserSettings() (userSettings, error) {
+	var settings userSettings
+	paths := []string{systemConfigPath}
+	if dir, err := userConfigDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, "config.yml"))
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return settings, err
+		}
+		var file userSettings
+		if err := yamlUnmarshal(data, &file); err != nil {
+			return settings, fmt.Errorf("%s: %v", path, err)
+		}
+		if file.Workspace != "" {
+			settings.Workspace = file.Workspace
+		}
+		if file.PolicyURL != "" {
+			settings.PolicyURL = file.PolicyURL
+		}
+		if file.Telemetry != nil {
+			settings.Telemetry = file.Telemetry
+		}
+		settings.Webhooks = append(settings.Webhooks, file.Webhooks...)
+		if file.SharedServer.Domain != "" {
+			settings.SharedServer.Domain = file.SharedServer.Domain
+		}
+		if file.SharedServer.Root != "" {
+			settings.SharedServer.Root = file.SharedServer.Root
+		}
+		settings.Defaults = settings.Defaults.overlay(file.Defaults)
+		for name, profile := range file.Profiles {
+			if settings.Profiles == nil {
+				settings.Profiles = map[string]userDefaults{}
+			}
+			settings.Profiles[name] = profile
+		}
+		if file.Archive.Bucket != "" {
+			settings.Archive = file.Archive
+		}
+	}
+	return settings, nil
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const watchCronMarker = "# drupal-scripts watch"
+
+func projectHealthy(url string) bool {
+	if url == "" {
+		return false
+	}
+	client := &http.Client{
+		Timeout:   15 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	resp, err := client.Get(url)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode < 500
+}
+
+func checkManagedProjects(restart, notify bool) {
+	projects, err := loadRegistry()
+	if err != nil {
+		printError(fmt.Sprintf("Could not read project registry: %v", err))
+		return
+	}
+	if len(projects) == 0 {
+		printWarning("No managed projects registered yet")
+		return
+	}
+
+	for _, p := range projects {
+		if _, err := os.Stat(p.Path); err != nil {
+			printWarning(fmt.Sprintf("%s: project directory %s is missing", p.Name, p.Path))
+			continue
+		}
+		if projectHealthy(p.URL) {
+			printSuccess(fmt.Sprintf("✓ %s is healthy (%s)", p.Name, p.URL))
+			continue
+		}
+
+		printWarning(fmt.Sprintf("✗ %s is not responding (%s)", p.Name, p.URL))
+		if !restart {
+			if notify {
+				sendDesktopNotification("Drupal project down", fmt.Sprintf("%s is not responding", p.Name))
+			}
+			continue
+		}
+
+		printStatus(fmt.Sprintf("Restarting %s...", p.Name))
+		if err := runDDEV(p.Path, "restart"); err == nil && projectHealthy(p.URL) {
+			printSuccess(fmt.Sprintf("✓ %s restarted", p.Name))
+			if notify {
+				sendDesktopNotification("Drupal project restarted", fmt.Sprintf("%s was unhealthy and has been restarted", p.Name))
+			}
+			continue
+		}
+		printError(fmt.Sprintf("%s is still down after restart", p.Name))
+		if notify {
+			sendDesktopNotification("Drupal project down", fmt.Sprintf("%s is still down after ddev restart", p.Name))
+		}
+	}
+}
+
+func updateWatchCron(install bool, interval int) error {
+	current, _ := exec.Command("crontab", "-l").Output()
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimRight(string(current), "\n"), "\n") {
+		if line != "" && !strings.HasSuffix(line, watchCronMarker) {
+			lines = append(lines, line)
+		}
+	}
+
+	if install {
+		binary, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		cronQuote := func(s string) string { return strings.ReplaceAll(shellQuote(s), "%", `\%`) }
+		entry := fmt.Sprintf("*/%d * * * * PATH=%s %s watch --once >/dev/null 2>&1 %s", interval, cronQuote(os.Getenv("PATH")), cronQuote(binary), watchCronMarker)
+		lines = append(lines, entry)
+	}
+
+	cmd := exec.Command("crontab", "-")
+	cmd.Stdin = strings.NewReader(strings.Join(lines, "\n") + "\n")
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+func runWatch(args []string) int {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	interval := fs.Duration("interval", 5*time.Minute, "Time between health checks")
+	once := fs.Bool("once", false, "Check all projects once and exit (for cron)")
+	restart := fs.Bool("restart", true, "Run 'ddev restart' on unhealthy projects")
+	notify := fs.Bool("notify", true, "Send a desktop notification when a project is unhealthy")
+	installCron := fs.Bool("install-cron", false, "Install a crontab entry that runs 'watch --once' at --interval")
+	uninstallCron := fs.Bool("uninstall-cron", false, "Remove the crontab entry added by --install-cron")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *installCron || *uninstallCron {
+		if !commandExists("crontab") {
+			printError("crontab is not available on this system")
+			return 1
+		}
+		minutes := int(interval.Minutes())
+		if minutes < 1 || minutes > 59 {
+			printError("--interval must be between 1m and 59m when installing a crontab entry")
+			return 2
+		}
+		if err := updateWatchCron(*installCron, minutes); err != nil {
+			printError(fmt.Sprintf("Failed to update crontab: %v", err))
+			return 1
+		}
+		if *installCron {
+			printSuccess(fmt.Sprintf("✓ Watch scheduled every %d minutes via crontab", minutes))
+		} else {
+			printSuccess("✓ Watch crontab entry removed")
+		}
+		return 0
+	}
+
+	for {
+		checkManagedProjects(*restart, *notify)
+		if *once {
+			return 0
+		}
+		time.Sleep(*interval)
+	}
+}
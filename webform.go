@@ -0,0 +1,64 @@
+package main
+
+const webformContactPHP = `use Drupal\webform\Entity\Webform;
+if (Webform::load('contact_us')) {
+  return;
+}
+$webform = Webform::create([
+  'id' => 'contact_us',
+  'title' => 'Contact us',
+  'description' => 'Example contact form created by drupal-scripts.',
+  'elements' => "name:\n  '#type': textfield\n  '#title': 'Your name'\n  '#required': true\nemail:\n  '#type': email\n  '#title': 'Your email'\n  '#required': true\nsubject:\n  '#type': textfield\n  '#title': Subject\nmessage:\n  '#type': textarea\n  '#title': Message\n  '#required': true\nactions:\n  '#type': webform_actions\n  '#submit__label': Send\n",
+]);
+$webform->setSetting('page_confirm_path', '');
+$webform->setSetting('confirmation_message', 'Thanks, we will get back to you soon.');
+$webform->setThirdPartySetting('honeypot', 'honeypot', TRUE);
+$webform->setThirdPartySetting('honeypot', 'honeypot_time_restriction', TRUE);
+$handler = \Drupal::service('plugin.manager.webform.handler')->createInstance('email', [
+  'id' => 'email',
+  'handler_id' => 'email_notification',
+  'label' => 'Email notification',
+  'status' => TRUE,
+  'weight' => 0,
+  'settings' => [
+    'to_mail' => '[site:mail]',
+    'reply_to' => '[webform_submission:values:email:raw]',
+    'subject' => 'Contact form: [webform_submission:values:subject]',
+  ],
+]);
+$webform->addWebformHandler($handler);
+$webform->save();`
+
+func init() {
+	registerPreset(&preset{
+		name:        "webform",
+		description: "Webform with an example contact form, email notifications (caught by Mailpit) and Honeypot spam protection",
+		packages:    []string{"drupal/webform", "drupal/honeypot"},
+		modules:     []string{"webform", "webform_ui", "honeypot"},
+		settings: []configSetting{
+			{name: "honeypot.settings", values: [][2]string{
+				{"element_name", "url"},
+				{"time_limit", "5"},
+				{"log", "true"},
+			}},
+		},
+		ignoreConfig: []string{"webform.webform.*", "webform.webform_options.*"},
+		tour: []tourStop{
+			{path: "/form/contact-us", label: "Example contact form (submissions are emailed to Mailpit: ddev launch -m)"},
+			{path: "/admin/structure/webform", label: "Webform administration"},
+		},
+		apply: applyWebformContact,
+	})
+}
+
+func applyWebformContact(projectPath string) error {
+	if err := runDDEVQuiet(projectPath, "drush", "php:eval", webformContactPHP); err != nil {
+		printError("Failed to create the example contact form")
+		return err
+	}
+	if err := exportConfig(projectPath, "webform.webform.contact_us"); err != nil {
+		return err
+	}
+	printSuccess("✓ Example contact form created; notification emails are caught by Mailpit (ddev launch -m)")
+	return nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+type webhookConfig struct {
+	URL    string   `yaml:"url"`
+	Type   string   `yaml:"type"`
+	Events []string `yaml:"events"`
+}
+
+func (w webhookConfig) webhookType() string {
+	if w.Type != "" {
+		return strings.ToLower(w.Type)
+	}
+	switch {
+	case strings.Contains(w.URL, "hooks.slack.com"):
+		return "slack"
+	case strings.Contains(w.URL, "discord.com/api/webhooks"), strings.Contains(w.URL, "discordapp.com/api/webhooks"):
+		return "discord"
+	case strings.Contains(w.URL, "webhook.office.com"), strings.Contains(w.URL, "logic.azure.com"):
+		return "teams"
+	}
+	return "json"
+}
+
+func (w webhookConfig) wants(event string) bool {
+	return len(w.Events) == 0 || containsString(w.Events, event)
+}
+
+func webhookPayload(kind, event, title, message string) any {
+	switch kind {
+	case "slack":
+		return map[string]string{"text": "*" + title + "*\n" + message}
+	case "discord":
+		return map[string]string{"content": "**" + title + "**\n" + message}
+	case "teams":
+		return map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  title,
+			"title":    title,
+			"text":     message,
+		}
+	}
+	host, _ := os.Hostname()
+	return map[string]string{
+		"event":   event,
+		"title":   title,
+		"message": message,
+		"host":    host,
+		"time":    time.Now().Format(time.RFC3339),
+	}
+}
+
+func sendWebhooks(event, title, message string) {
+	settings, err := loadUserSettings()
+	if err != nil {
+		printWarning(fmt.Sprintf("Could not read webhook settings: %v", err))
+		return
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	for _, hook := range settings.Webhooks {
+		if hook.URL == "" || !hook.wants(event) {
+			continue
+		}
+		body, err := json.Marshal(webhookPayload(hook.webhookType(), event, title, message))
+		if err != nil {
+			continue
+		}
+		resp, err := client.Post(hook.URL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			printWarning(fmt.Sprintf("Webhook %s failed: %v", hook.webhookType(), err))
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			printWarning(fmt.Sprintf("Webhook %s returned %s", hook.webhookType(), resp.Status))
+		}
+	}
+}
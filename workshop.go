@@ -0,0 +1,201 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"text/tabwriter"
+)
+
+type workshopSite struct {
+	name          string
+	url           string
+	adminPassword string
+	basicAuth     *basicAuthCredentials
+	err           error
+}
+
+func workshopSiteName(prefix string, index, count int) string {
+	width := max(len(fmt.Sprint(count)), 2)
+	return fmt.Sprintf("%s-%0*d", prefix, width, index)
+}
+
+func writeWorkshopHostname(projectPath, hostname string) error {
+	content := "# Managed by drupal-scripts workshop.\n" +
+		"additional_fqdns:\n" +
+		"  - " + hostname + "\n"
+	return writeFile(filepath.Join(projectPath, ".ddev", "config.workshop.yaml"), []byte(content))
+}
+
+func readBasicAuthCredentials(projectName string) *basicAuthCredentials {
+	path, err := basicAuthCredentialsPath(projectName)
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	creds := &basicAuthCredentials{}
+	if err := json.Unmarshal(data, creds); err != nil {
+		return nil
+	}
+	return creds
+}
+
+func provisionWorkshopSite(exe, name, domain string, basicAuth bool, installArgs []string) workshopSite {
+	site := workshopSite{name: name}
+
+	password, err := randomString(apr1Alphabet[2:], 12)
+	if err != nil {
+		site.err = err
+		return site
+	}
+	site.adminPassword = password
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		site.err = err
+		return site
+	}
+	args := []string{"--project-name", name, "--admin-password", password, "--path", cwd, "--notify=false"}
+	if basicAuth {
+		args = append(args, "--basic-auth")
+	}
+	args = append(args, installArgs...)
+
+	cmd := exec.Command(exe, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		site.err = err
+		return site
+	}
+
+	projectPath := filepath.Join(cwd, name)
+	if registered, ok := findRegisteredProject(projectPath); ok {
+		site.url = registered.URL
+	}
+
+	if domain != "" {
+		hostname := name + "." + domain
+		if err := writeWorkshopHostname(projectPath, hostname); err != nil {
+			site.err = err
+			return site
+		}
+		if err := runDDEVQuiet(projectPath, "restart"); err != nil {
+			site.err = fmt.Errorf("ddev restart failed: %v", err)
+			return site
+		}
+		site.url = "https://" + hostname
+		registerProject(projectPath, site.url, nil)
+	}
+
+	if basicAuth {
+		site.basicAuth = readBasicAuthCredentials(name)
+	}
+	return site
+}
+
+func writeWorkshopRoster(path string, sites []workshopSite) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, secretPerm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"site", "url", "admin_user", "admin_password", "basic_auth_user", "basic_auth_password", "status"})
+	for _, s := range sites {
+		var authUser, authPass string
+		if s.basicAuth != nil {
+			authUser, authPass = s.basicAuth.Username, s.basicAuth.Password
+		}
+		status := "ok"
+		if s.err != nil {
+			status = "failed: " + s.err.Error()
+		}
+		w.Write([]string{s.name, s.url, "admin", s.adminPassword, authUser, authPass, status})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func printWorkshopRoster(sites []workshopSite) {
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SITE\tURL\tADMIN LOGIN\tBASIC AUTH")
+	for _, s := range sites {
+		if s.err != nil {
+			fmt.Fprintf(w, "%s\tFAILED: %v\t\t\n", s.name, s.err)
+			continue
+		}
+		auth := "-"
+		if s.basicAuth != nil {
+			auth = s.basicAuth.Username + " / " + s.basicAuth.Password
+		}
+		fmt.Fprintf(w, "%s\t%s\tadmin / %s\t%s\n", s.name, s.url, s.adminPassword, auth)
+	}
+	w.Flush()
+}
+
+func runWorkshop(args []string) int {
+	fs := flag.NewFlagSet("workshop", flag.ContinueOnError)
+	count := fs.Int("count", 0, "Number of identical sites to provision")
+	prefix := fs.String("prefix", "site", "Project name prefix; sites are named <prefix>-01..<prefix>-N")
+	domain := fs.String("domain", "", "Serve each site at <name>.<domain> in addition to its .ddev.site hostname (for shared servers)")
+	basicAuth := fs.Bool("basic-auth", false, "Protect each site with its own generated HTTP basic auth credentials")
+	roster := fs.String("roster", "workshop-roster.csv", "Where to write the roster of URLs and credentials")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: drupal-installer workshop --count N [flags] [-- install flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if *count < 1 {
+		printError("--count must be at least 1")
+		return 2
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		printError(fmt.Sprintf("Cannot locate the installer binary: %v", err))
+		return 1
+	}
+
+	var sites []workshopSite
+	for i := 1; i <= *count; i++ {
+		name := workshopSiteName(*prefix, i, *count)
+		fmt.Println()
+		printStatus(fmt.Sprintf("Provisioning %s (%d/%d)...", name, i, *count))
+		site := provisionWorkshopSite(exe, name, *domain, *basicAuth, fs.Args())
+		if site.err != nil {
+			printError(fmt.Sprintf("Failed to provision %s: %v", name, site.err))
+		}
+		sites = append(sites, site)
+	}
+
+	printWorkshopRoster(sites)
+	if err := writeWorkshopRoster(*roster, sites); err != nil {
+		printWarning(fmt.Sprintf("Could not write roster: %v", err))
+	} else {
+		printSuccess(fmt.Sprintf("✓ Roster written to %s", *roster))
+	}
+
+	failed := 0
+	for _, s := range sites {
+		if s.err != nil {
+			failed++
+		}
+	}
+	sendWebhooks("workshop", "Workshop provisioning finished", fmt.Sprintf("%d of %d sites ready; roster in %s", len(sites)-failed, len(sites), *roster))
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+func projectParentDir() (string, error) {
+	if sharedMode() {
+		if opts.path != "" {
+			printWarning("Ignoring --path: shared server mode creates projects in your namespace")
+		}
+		return ensureSharedProjectsDir()
+	}
+	dir := opts.path
+	if dir == "" {
+		settings, err := loadUserSettings()
+		if err != nil {
+			return "", err
+		}
+		dir = settings.Workspace
+	}
+	if dir == "" {
+		return os.Getwd()
+	}
+	dir, err := filepath.Abs(expandHome(dir))
+	if err != nil {
+		return "", err
+	}
+	if err := ensureDir(dir); err != nil {
+		return "", fmt.Errorf("could not create workspace %s: %v", dir, err)
+	}
+	printStatus(fmt.Sprintf("Creating the project in %s", dir))
+	return dir, nil
+}
@@ -0,0 +1,677 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type yamlScalar struct {
+	value  string
+	quoted bool
+}
+
+type yamlMap struct {
+	keys   []string
+	values map[string]any
+}
+
+func (m *yamlMap) set(key string, value any) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+type yamlNodeUnmarshaler interface {
+	unmarshalYAMLNode(node any) error
+}
+
+type yamlLine struct {
+	num    int
+	indent int
+	raw    string
+	text   string
+	blank  bool
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func yamlUnmarshal(data []byte, v any) error {
+	node, err := yamlParse(string(data))
+	if err != nil {
+		return err
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("yaml: decode target must be a non-nil pointer")
+	}
+	return yamlDecode(node, rv.Elem(), "")
+}
+
+func yamlParse(src string) (any, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n") {
+		raw = strings.TrimRight(raw, " \t")
+		trimmed := strings.TrimLeft(raw, " ")
+		line := yamlLine{num: i + 1, indent: len(raw) - len(trimmed), raw: raw}
+		line.text = strings.TrimSpace(stripYAMLComment(trimmed))
+		line.blank = line.text == "" || line.text == "---"
+		p.lines = append(p.lines, line)
+	}
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	node, err := p.parseBlock(p.lines[p.pos].indent)
+	if err != nil {
+		return nil, err
+	}
+	p.skipBlank()
+	if p.pos < len(p.lines) {
+		return nil, fmt.Errorf("yaml: line %d: unexpected content", p.lines[p.pos].num)
+	}
+	return node, nil
+}
+
+func stripYAMLComment(s string) string {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote == '\'':
+			if c == '\'' {
+				if i+1 < len(s) && s[i+1] == '\'' {
+					i++
+					continue
+				}
+				quote = 0
+			}
+		case quote == '"':
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				quote = 0
+			}
+		case (c == '\'' || c == '"') && yamlScalarStart(s[:i]):
+			quote = c
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+	return s
+}
+
+func yamlScalarStart(before string) bool {
+	before = strings.TrimRight(before, " \t")
+	return before == "" || strings.IndexByte(":-[{,", before[len(before)-1]) >= 0
+}
+
+func (p *yamlParser) skipBlank() {
+	for p.pos < len(p.lines) && p.lines[p.pos].blank {
+		p.pos++
+	}
+}
+
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+func (p *yamlParser) parseBlock(indent int) (any, error) {
+	p.skipBlank()
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	if isSequenceItem(p.lines[p.pos].text) {
+		return p.parseSequence(indent)
+	}
+	if _, _, ok := splitYAMLKey(p.lines[p.pos].text); ok {
+		return p.parseMapping(indent)
+	}
+	line := p.lines[p.pos]
+	p.pos++
+	return parseYAMLValue(line.text, line.num)
+}
+
+func (p *yamlParser) parseSequence(indent int) (any, error) {
+	items := []any{}
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.lines) {
+			break
+		}
+		line := p.lines[p.pos]
+		if line.indent != indent || !isSequenceItem(line.text) {
+			if line.indent > indent {
+				return nil, fmt.Errorf("yaml: line %d: bad indentation", line.num)
+			}
+			break
+		}
+
+		rest := strings.TrimSpace(strings.TrimPrefix(line.text, "-"))
+		if rest == "" {
+			p.pos++
+			p.skipBlank()
+			if p.pos < len(p.lines) && p.lines[p.pos].indent > indent {
+				item, err := p.parseBlock(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				items = append(items, item)
+			} else {
+				items = append(items, nil)
+			}
+			continue
+		}
+
+		offset := strings.Index(line.raw, rest)
+		if _, _, ok := splitYAMLKey(rest); ok || isSequenceItem(rest) {
+			p.lines[p.pos].indent = offset
+			p.lines[p.pos].text = rest
+			item, err := p.parseBlock(offset)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+
+		p.pos++
+		item, err := p.parseScalarValue(rest, line, indent)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (p *yamlParser) parseMapping(indent int) (any, error) {
+	m := &yamlMap{values: map[string]any{}}
+	for {
+		p.skipBlank()
+		if p.pos >= len(p.lines) {
+			break
+		}
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, fmt.Errorf("yaml: line %d: bad indentation", line.num)
+		}
+		key, rest, ok := splitYAMLKey(line.text)
+		if !ok {
+			if isSequenceItem(line.text) {
+				break
+			}
+			return nil, fmt.Errorf("yaml: line %d: expected key: value", line.num)
+		}
+		p.pos++
+
+		if rest == "" {
+			p.skipBlank()
+			switch {
+			case p.pos < len(p.lines) && p.lines[p.pos].indent > indent:
+				value, err := p.parseBlock(p.lines[p.pos].indent)
+				if err != nil {
+					return nil, err
+				}
+				m.set(key, value)
+			case p.pos < len(p.lines) && p.lines[p.pos].indent == indent && isSequenceItem(p.lines[p.pos].text):
+				value, err := p.parseSequence(indent)
+				if err != nil {
+					return nil, err
+				}
+				m.set(key, value)
+			default:
+				m.set(key, nil)
+			}
+			continue
+		}
+
+		value, err := p.parseScalarValue(rest, line, indent)
+		if err != nil {
+			return nil, err
+		}
+		m.set(key, value)
+	}
+	return m, nil
+}
+
+func (p *yamlParser) parseScalarValue(text string, line yamlLine, indent int) (any, error) {
+	if len(text) > 0 && (text[0] == '|' || text[0] == '>') {
+		return p.parseBlockScalar(text, indent), nil
+	}
+	return parseYAMLValue(text, line.num)
+}
+
+func (p *yamlParser) parseBlockScalar(header string, indent int) any {
+	folded := header[0] == '>'
+	chomp := byte(0)
+	if len(header) > 1 {
+		chomp = header[1]
+	}
+
+	var body []string
+	blockIndent := -1
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if strings.TrimSpace(line.raw) == "" {
+			body = append(body, "")
+			p.pos++
+			continue
+		}
+		if line.indent <= indent {
+			break
+		}
+		if blockIndent < 0 {
+			blockIndent = line.indent
+		}
+		if line.indent < blockIndent {
+			break
+		}
+		body = append(body, line.raw[blockIndent:])
+		p.pos++
+	}
+
+	trailing := 0
+	for len(body) > 0 && body[len(body)-1] == "" {
+		body = body[:len(body)-1]
+		trailing++
+	}
+
+	var text string
+	if folded {
+		var b strings.Builder
+		for i, l := range body {
+			switch {
+			case i == 0:
+			case l == "" || body[i-1] == "":
+				b.WriteString("\n")
+			default:
+				b.WriteString(" ")
+			}
+			b.WriteString(l)
+		}
+		text = b.String()
+	} else {
+		text = strings.Join(body, "\n")
+	}
+
+	switch chomp {
+	case '-':
+	case '+':
+		text += strings.Repeat("\n", trailing+1)
+	default:
+		if len(body) > 0 {
+			text += "\n"
+		}
+	}
+	return yamlScalar{value: text, quoted: true}
+}
+
+func splitYAMLKey(text string) (string, string, bool) {
+	if text == "" || text[0] == '[' || text[0] == '{' || isSequenceItem(text) {
+		return "", "", false
+	}
+	if text[0] == '"' || text[0] == '\'' {
+		end := strings.IndexByte(text[1:], text[0])
+		if end < 0 {
+			return "", "", false
+		}
+		rest := strings.TrimSpace(text[end+2:])
+		if !strings.HasPrefix(rest, ":") {
+			return "", "", false
+		}
+		return text[1 : end+1], strings.TrimSpace(rest[1:]), true
+	}
+	idx := strings.Index(text, ": ")
+	if idx < 0 {
+		if strings.HasSuffix(text, ":") {
+			return strings.TrimSpace(text[:len(text)-1]), "", true
+		}
+		return "", "", false
+	}
+	return strings.TrimSpace(text[:idx]), strings.TrimSpace(text[idx+2:]), true
+}
+
+func parseYAMLValue(text string, lineNum int) (any, error) {
+	if text == "" {
+		return nil, nil
+	}
+	if text[0] == '[' || text[0] == '{' {
+		f := &yamlFlow{src: text}
+		node, err := f.parse()
+		if err != nil {
+			return nil, fmt.Errorf("yaml: line %d: %v", lineNum, err)
+		}
+		return node, nil
+	}
+	return parseYAMLScalar(text)
+}
+
+func parseYAMLScalar(text string) (any, error) {
+	switch {
+	case len(text) >= 2 && text[0] == '\'' && text[len(text)-1] == '\'':
+		return yamlScalar{value: strings.ReplaceAll(text[1:len(text)-1], "''", "'"), quoted: true}, nil
+	case len(text) >= 2 && text[0] == '"' && text[len(text)-1] == '"':
+		value, err := strconv.Unquote(text)
+		if err != nil {
+			return nil, fmt.Errorf("yaml: invalid double-quoted string %s", text)
+		}
+		return yamlScalar{value: value, quoted: true}, nil
+	case text == "~" || text == "null" || text == "Null" || text == "NULL":
+		return nil, nil
+	}
+	return yamlScalar{value: text}, nil
+}
+
+type yamlFlow struct {
+	src string
+	pos int
+}
+
+func (f *yamlFlow) skipSpace() {
+	for f.pos < len(f.src) && (f.src[f.pos] == ' ' || f.src[f.pos] == '\t') {
+		f.pos++
+	}
+}
+
+func (f *yamlFlow) parse() (any, error) {
+	node, err := f.parseNode()
+	if err != nil {
+		return nil, err
+	}
+	f.skipSpace()
+	if f.pos != len(f.src) {
+		return nil, fmt.Errorf("unexpected %q in flow collection", f.src[f.pos:])
+	}
+	return node, nil
+}
+
+func (f *yamlFlow) parseNode() (any, error) {
+	f.skipSpace()
+	if f.pos >= len(f.src) {
+		return nil, fmt.Errorf("unterminated flow collection")
+	}
+	switch f.src[f.pos] {
+	case '[':
+		f.pos++
+		items := []any{}
+		for {
+			f.skipSpace()
+			if f.pos < len(f.src) && f.src[f.pos] == ']' {
+				f.pos++
+				return items, nil
+			}
+			item, err := f.parseNode()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			if err := f.separator(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		f.pos++
+		m := &yamlMap{values: map[string]any{}}
+		for {
+			f.skipSpace()
+			if f.pos < len(f.src) && f.src[f.pos] == '}' {
+				f.pos++
+				return m, nil
+			}
+			key, err := f.parseNode()
+			if err != nil {
+				return nil, err
+			}
+			ks, ok := key.(yamlScalar)
+			if !ok {
+				return nil, fmt.Errorf("flow mapping keys must be scalars")
+			}
+			f.skipSpace()
+			if f.pos >= len(f.src) || f.src[f.pos] != ':' {
+				return nil, fmt.Errorf("expected ':' after key %q", ks.value)
+			}
+			f.pos++
+			value, err := f.parseNode()
+			if err != nil {
+				return nil, err
+			}
+			m.set(ks.value, value)
+			if err := f.separator('}'); err != nil {
+				return nil, err
+			}
+		}
+	case '\'', '"':
+		quote := f.src[f.pos]
+		end := f.pos + 1
+		for end < len(f.src) {
+			if f.src[end] == quote {
+				if quote == '\'' && end+1 < len(f.src) && f.src[end+1] == '\'' {
+					end += 2
+					continue
+				}
+				break
+			}
+			if quote == '"' && f.src[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(f.src) {
+			return nil, fmt.Errorf("unterminated string")
+		}
+		text := f.src[f.pos : end+1]
+		f.pos = end + 1
+		return parseYAMLScalar(text)
+	}
+
+	start := f.pos
+	for f.pos < len(f.src) && !strings.ContainsRune(",]}", rune(f.src[f.pos])) {
+		if f.src[f.pos] == ':' && (f.pos+1 >= len(f.src) || f.src[f.pos+1] == ' ') {
+			break
+		}
+		f.pos++
+	}
+	return parseYAMLScalar(strings.TrimSpace(f.src[start:f.pos]))
+}
+
+func (f *yamlFlow) separator(closer byte) error {
+	f.skipSpace()
+	if f.pos >= len(f.src) {
+		return fmt.Errorf("unterminated flow collection")
+	}
+	switch f.src[f.pos] {
+	case ',':
+		f.pos++
+		return nil
+	case closer:
+		return nil
+	}
+	return fmt.Errorf("unexpected %q in flow collection", f.src[f.pos])
+}
+
+func yamlNatural(node any) any {
+	switch n := node.(type) {
+	case *yamlMap:
+		out := make(map[string]any, len(n.values))
+		for k, v := range n.values {
+			out[k] = yamlNatural(v)
+		}
+		return out
+	case []any:
+		out := make([]any, len(n))
+		for i, v := range n {
+			out[i] = yamlNatural(v)
+		}
+		return out
+	case yamlScalar:
+		if n.quoted {
+			return n.value
+		}
+		if b, ok := parseYAMLBool(n.value); ok {
+			return b
+		}
+		if i, err := strconv.ParseInt(n.value, 10, 64); err == nil {
+			return i
+		}
+		if f, err := strconv.ParseFloat(n.value, 64); err == nil {
+			return f
+		}
+		return n.value
+	}
+	return nil
+}
+
+func parseYAMLBool(s string) (bool, bool) {
+	switch strings.ToLower(s) {
+	case "true", "yes", "on":
+		return true, true
+	case "false", "no", "off":
+		return false, true
+	}
+	return false, false
+}
+
+func yamlFieldName(field reflect.StructField) string {
+	tag := field.Tag.Get("yaml")
+	if tag == "" {
+		return strings.ToLower(field.Name)
+	}
+	return strings.Split(tag, ",")[0]
+}
+
+func yamlDecode(node any, rv reflect.Value, path string) error {
+	if node == nil {
+		return nil
+	}
+	where := path
+	if where == "" {
+		where = "document"
+	}
+
+	if rv.CanAddr() {
+		if u, ok := rv.Addr().Interface().(yamlNodeUnmarshaler); ok {
+			if err := u.unmarshalYAMLNode(node); err != nil {
+				return fmt.Errorf("yaml: %s: %v", where, err)
+			}
+			return nil
+		}
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			rv.Set(reflect.New(rv.Type().Elem()))
+		}
+		return yamlDecode(node, rv.Elem(), path)
+	case reflect.Interface:
+		rv.Set(reflect.ValueOf(yamlNatural(node)))
+		return nil
+	case reflect.Struct:
+		m, ok := node.(*yamlMap)
+		if !ok {
+			return fmt.Errorf("yaml: %s: expected a mapping", where)
+		}
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name := yamlFieldName(field)
+			if name == "-" || !field.IsExported() {
+				continue
+			}
+			value, ok := m.values[name]
+			if !ok {
+				continue
+			}
+			if err := yamlDecode(value, rv.Field(i), joinYAMLPath(path, name)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		m, ok := node.(*yamlMap)
+		if !ok {
+			return fmt.Errorf("yaml: %s: expected a mapping", where)
+		}
+		if rv.IsNil() {
+			rv.Set(reflect.MakeMap(rv.Type()))
+		}
+		for _, k := range m.keys {
+			v := m.values[k]
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			if err := yamlDecode(v, elem, joinYAMLPath(path, k)); err != nil {
+				return err
+			}
+			rv.SetMapIndex(reflect.ValueOf(k).Convert(rv.Type().Key()), elem)
+		}
+		return nil
+	case reflect.Slice:
+		items, ok := node.([]any)
+		if !ok {
+			if s, isScalar := node.(yamlScalar); isScalar && rv.Type().Elem().Kind() == reflect.String {
+				items = []any{s}
+			} else {
+				return fmt.Errorf("yaml: %s: expected a sequence", where)
+			}
+		}
+		out := reflect.MakeSlice(rv.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := yamlDecode(item, out.Index(i), fmt.Sprintf("%s[%d]", where, i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(out)
+		return nil
+	}
+
+	s, ok := node.(yamlScalar)
+	if !ok {
+		return fmt.Errorf("yaml: %s: expected a scalar value", where)
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(s.value)
+	case reflect.Bool:
+		b, ok := parseYAMLBool(s.value)
+		if !ok {
+			return fmt.Errorf("yaml: %s: invalid boolean %q", where, s.value)
+		}
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s.value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("yaml: %s: invalid integer %q", where, s.value)
+		}
+		rv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(s.value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("yaml: %s: invalid integer %q", where, s.value)
+		}
+		rv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s.value, 64)
+		if err != nil {
+			return fmt.Errorf("yaml: %s: invalid number %q", where, s.value)
+		}
+		rv.SetFloat(f)
+	default:
+		return fmt.Errorf("yaml: %s: unsupported target type %s", where, rv.Type())
+	}
+	return nil
+}
+
+func joinYAMLPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
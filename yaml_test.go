@@ -0,0 +1,109 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStripYAMLComment(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"name: site", "name: site"},
+		{"name: site # comment", "name: site "},
+		{"# whole line", ""},
+		{"name: Bob's site # x", "name: Bob's site "},
+		{"name: rock 'n roll # x", "name: rock 'n roll "},
+		{"name: 'a # b' # x", "name: 'a # b' "},
+		{"name: 'it''s # here' # x", "name: 'it''s # here' "},
+		{`name: "a \" # b" # x`, `name: "a \" # b" `},
+		{"- 'a # b' # x", "- 'a # b' "},
+		{"list: [a, 'b # c'] # x", "list: [a, 'b # c'] "},
+		{"url: http://example.com/#anchor", "url: http://example.com/#anchor"},
+		{"'quoted key': value # x", "'quoted key': value "},
+	}
+	for _, tt := range tests {
+		if got := stripYAMLComment(tt.in); got != tt.want {
+			t.Errorf("stripYAMLComment(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestYAMLParse(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want any
+	}{
+		{"empty", "", nil},
+		{"scalars", "name: Bob's site # owner\ncount: 3\nratio: 0.5\nenabled: yes\nnothing: ~\n", map[string]any{
+			"name": "Bob's site", "count": int64(3), "ratio": 0.5, "enabled": true, "nothing": nil,
+		}},
+		{"quoted", "single: 'it''s'\ndouble: \"tab\\tend\"\nnumber: '3'\n", map[string]any{
+			"single": "it's", "double": "tab\tend", "number": "3",
+		}},
+		{"nested", "project:\n  name: demo\n  modules:\n    - admin_toolbar\n    - pathauto # comment\n", map[string]any{
+			"project": map[string]any{"name": "demo", "modules": []any{"admin_toolbar", "pathauto"}},
+		}},
+		{"sequence of maps", "- name: a\n  port: 80\n- name: b\n", []any{
+			map[string]any{"name": "a", "port": int64(80)},
+			map[string]any{"name": "b"},
+		}},
+		{"flow", "list: [a, 'b, c', 1]\nmap: {x: 1, y: two}\n", map[string]any{
+			"list": []any{"a", "b, c", int64(1)},
+			"map":  map[string]any{"x": int64(1), "y": "two"},
+		}},
+		{"block scalar", "script: |\n  line one\n  line two\nafter: x\n", map[string]any{
+			"script": "line one\nline two\n", "after": "x",
+		}},
+		{"document marker", "---\nname: demo\n", map[string]any{"name": "demo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := yamlParse(tt.src)
+			if err != nil {
+				t.Fatalf("yamlParse: %v", err)
+			}
+			if got := yamlNatural(node); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYAMLParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"unterminated flow", "list: [a, b\n"},
+		{"bad double quote", "name: \"a\\q\"\n"},
+		{"outdented content", "a:\n    b: 1\n  c: 2\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := yamlParse(tt.src); err == nil {
+				t.Errorf("yamlParse(%q) succeeded, want an error", tt.src)
+			}
+		})
+	}
+}
+
+func TestYAMLUnmarshal(t *testing.T) {
+	src := "name: Acme's policy # org\nrequired_modules: [seckit, login_security]\nbanned_modules:\n  - php\nminimum_php: '8.3'\nmandatory_config:\n  system.performance:\n    css.preprocess: 'true'\n"
+	var policy orgPolicy
+	if err := yamlUnmarshal([]byte(src), &policy); err != nil {
+		t.Fatal(err)
+	}
+	want := orgPolicy{
+		Name:            "Acme's policy",
+		RequiredModules: []string{"seckit", "login_security"},
+		BannedModules:   []string{"php"},
+		MinimumPHP:      "8.3",
+		MandatoryConfig: map[string]map[string]string{"system.performance": {"css.preprocess": "true"}},
+	}
+	if !reflect.DeepEqual(policy, want) {
+		t.Errorf("got %#v, want %#v", policy, want)
+	}
+}